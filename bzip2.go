@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// Sizes of the compressed segments decoded by a single worker. If no stream boundary is found within
+// ParallelSegmentMax bytes, the rest of the input is decoded sequentially.
+const (
+	ParallelSegmentSize = 1 << 20
+	ParallelSegmentMax  = 16 << 20
+)
+
+// bzip2BlockMagic follows the stream header ("BZh" and the block size) at the start of each bzip2 stream.
+var bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+
+// bzip2Segment is a part of the input holding one or more complete bzip2 streams.
+type bzip2Segment struct {
+	data   []byte           // Compressed streams
+	result chan bzip2Result // Receives the decompressed data
+}
+
+// bzip2Result is the outcome of decompressing a single segment.
+type bzip2Result struct {
+	data   []byte    // Decompressed data
+	stream io.Reader // Decompresses the rest of the input sequentially (instead of data)
+	err    error     // Error while reading or decompressing
+}
+
+// ParallelBzip2Reader decompresses multi-stream bzip2 files (as created by pbzip2 or the "multistream" dumps of
+// Wikipedia) by splitting them at stream boundaries and decoding the streams concurrently. Files with a single
+// stream are decoded by a single worker.
+type ParallelBzip2Reader struct {
+	order  chan *bzip2Segment // Segments in input order
+	done   chan struct{}      // Closed to stop all goroutines
+	once   sync.Once          // Closes done once
+	buf    []byte             // Decompressed data not read yet
+	stream io.Reader          // Sequential decompression of the rest of the input
+	err    error              // Sticky error
+}
+
+// NewParallelBzip2Reader returns a reader decompressing r with the given number of workers.
+func NewParallelBzip2Reader(r io.Reader, workers int) *ParallelBzip2Reader {
+	pr := &ParallelBzip2Reader{
+		order: make(chan *bzip2Segment, 2*workers),
+		done:  make(chan struct{}),
+	}
+
+	jobs := make(chan *bzip2Segment, workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for seg := range jobs {
+				data, err := ioutil.ReadAll(bzip2.NewReader(bytes.NewReader(seg.data)))
+				seg.result <- bzip2Result{data: data, err: err}
+			}
+		}()
+	}
+
+	go pr.split(r, jobs)
+
+	return pr
+}
+
+// split reads r, cuts it into segments at stream boundaries, and hands them to the workers in order.
+func (pr *ParallelBzip2Reader) split(r io.Reader, jobs chan *bzip2Segment) {
+	defer close(pr.order)
+	defer close(jobs)
+
+	send := func(seg *bzip2Segment) bool {
+		select {
+		case pr.order <- seg:
+		case <-pr.done:
+			return false
+		}
+
+		select {
+		case jobs <- seg:
+			return true
+		case <-pr.done:
+			return false
+		}
+	}
+
+	var pending []byte
+	chunk := make([]byte, 256<<10)
+	last := -1
+
+	for {
+		n, err := r.Read(chunk)
+
+		// Find stream boundaries in the new data (including the end of the previous chunk)
+		from := len(pending) - 9
+		if from < 1 {
+			from = 1
+		}
+
+		pending = append(pending, chunk[:n]...)
+
+		if i := lastStreamStart(pending, from); i > 0 {
+			last = i
+		}
+
+		// Cut at the last stream boundary once the segment is large enough
+		if len(pending) >= ParallelSegmentSize && last > 0 {
+			seg := &bzip2Segment{data: pending[:last], result: make(chan bzip2Result, 1)}
+			pending = append([]byte(nil), pending[last:]...)
+			last = -1
+
+			if !send(seg) {
+				return
+			}
+		}
+
+		// Decode the rest sequentially if there are no boundaries (i.e. single stream files)
+		if len(pending) >= ParallelSegmentMax && err == nil {
+			seg := &bzip2Segment{result: make(chan bzip2Result, 1)}
+			seg.result <- bzip2Result{stream: bzip2.NewReader(io.MultiReader(bytes.NewReader(pending), r))}
+
+			select {
+			case pr.order <- seg:
+			case <-pr.done:
+			}
+
+			return
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			seg := &bzip2Segment{result: make(chan bzip2Result, 1)}
+			seg.result <- bzip2Result{err: err}
+
+			select {
+			case pr.order <- seg:
+			case <-pr.done:
+			}
+
+			return
+		}
+	}
+
+	if len(pending) > 0 {
+		send(&bzip2Segment{data: pending, result: make(chan bzip2Result, 1)})
+	}
+}
+
+// lastStreamStart returns the offset of the last bzip2 stream header in data starting at or after from, or -1
+// if there is none.
+func lastStreamStart(data []byte, from int) int {
+	for end := len(data); end > from; {
+		i := bytes.LastIndex(data[from:end], []byte("BZh"))
+		if i < 0 {
+			return -1
+		}
+
+		i += from
+
+		if isStreamStart(data[i:]) {
+			return i
+		}
+
+		end = i
+	}
+
+	return -1
+}
+
+// isStreamStart returns true if data starts with a bzip2 stream header followed by the first block.
+func isStreamStart(data []byte) bool {
+	return len(data) >= 10 &&
+		data[3] >= '1' && data[3] <= '9' &&
+		bytes.Equal(data[4:10], bzip2BlockMagic)
+}
+
+// Read implements io.Reader.
+func (pr *ParallelBzip2Reader) Read(p []byte) (int, error) {
+	for len(pr.buf) == 0 {
+		if pr.stream != nil {
+			n, err := pr.stream.Read(p)
+			if err == io.EOF {
+				pr.stream = nil
+				err = nil
+			}
+
+			if n > 0 || err != nil {
+				return n, err
+			}
+
+			continue
+		}
+
+		if pr.err != nil {
+			return 0, pr.err
+		}
+
+		seg, ok := <-pr.order
+		if !ok {
+			pr.err = io.EOF
+			continue
+		}
+
+		res := <-seg.result
+		if res.err != nil {
+			pr.err = fmt.Errorf("unable to decompress bzip2 stream: %w", res.err)
+			continue
+		}
+
+		pr.buf = res.data
+		pr.stream = res.stream
+	}
+
+	n := copy(p, pr.buf)
+	pr.buf = pr.buf[n:]
+
+	return n, nil
+}
+
+// Close stops all workers.
+func (pr *ParallelBzip2Reader) Close() error {
+	pr.once.Do(func() { close(pr.done) })
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+)
+
+// Charset is a set of characters names are restricted to.
+type Charset func(r rune) bool
+
+// ParseCharset parses a character set given as "ascii" (ASCII letters), "alnum" (ASCII letters and digits), or
+// as the allowed characters themselves (i.e. "abcdefghijklmnopqrstuvwxyzäöü").
+func ParseCharset(s string) Charset {
+	switch s {
+	case "ascii":
+		return isASCIILetter
+	case "alnum":
+		return func(r rune) bool {
+			return isASCIILetter(r) || (r >= '0' && r <= '9')
+		}
+	default:
+		return func(r rune) bool {
+			return strings.ContainsRune(s, r)
+		}
+	}
+}
+
+// Allows returns true if all characters of s are in the set.
+func (cs Charset) Allows(s string) bool {
+	for _, r := range s {
+		if !cs(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AllowsTokens returns true if all characters of the space separated tokens of s are in the set, so that the
+// space joining multiple first names (i.e. "Anna Maria") is always allowed.
+func (cs Charset) AllowsTokens(s string) bool {
+	for _, token := range strings.Split(s, " ") {
+		if !cs.Allows(token) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isASCIILetter returns true if r is a letter of the ASCII alphabet.
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestCharset(t *testing.T) {
+	tests := []struct {
+		charset string
+		name    string
+		want    bool
+	}{
+		{"ascii", "Anna", true},
+		{"ascii", "José", false},
+		{"ascii", "Anna Maria", true},
+		{"ascii", "Anna José", false},
+		{"ascii", "Anna2", false},
+		{"alnum", "Anna2", true},
+		{"alnum", "Anna Maria", true},
+		{"abnr", "anna", true},
+		{"abnr", "anna bea", false},
+		{"abenr", "anna bea", true},
+	}
+
+	for _, tt := range tests {
+		if got := ParseCharset(tt.charset).AllowsTokens(tt.name); got != tt.want {
+			t.Errorf("%s: AllowsTokens(%q) = %v, want %v", tt.charset, tt.name, got, tt.want)
+		}
+	}
+
+	// The space is only allowed between tokens
+	if ParseCharset("ascii").Allows("Anna Maria") {
+		t.Error("space allowed in a single token")
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// PasswordHashes holds known password hashes (lower case hex) with their number of occurrences.
+type PasswordHashes struct {
+	Counts map[string]int // Number of occurrences per hash
+	MD5    bool           // Set if any hash is an MD5 hash
+	SHA1   bool           // Set if any hash is a SHA-1 hash
+}
+
+// CheckResult holds the outcome of checking a wordlist against known password hashes.
+type CheckResult struct {
+	Entries   int             // Number of wordlist entries
+	Passwords int             // Number of passwords (including duplicates)
+	Unique    int             // Number of unique passwords
+	Found     map[string]bool // Hashes found in the wordlist
+	Hits      int             // Number of passwords found (including duplicates)
+}
+
+// check is called for the "check" sub command.
+func check(cmd *cobra.Command, args []string) {
+	// Load known passwords
+	pf, err := os.Open(args[1])
+	if err != nil {
+		logrus.Errorf("Unable to open password file: %v", err)
+		os.Exit(1)
+	}
+
+	hashes, err := LoadPasswordHashes(pf)
+	pf.Close()
+
+	if err != nil {
+		logrus.Errorf("Unable to load password file: %v", err)
+		os.Exit(1)
+	}
+
+	// Open wordlist
+	f, err := os.Open(args[0])
+	if err != nil {
+		logrus.Errorf("Unable to open wordlist: %v", err)
+		os.Exit(1)
+	}
+
+	defer f.Close()
+
+	// Check and print results
+	res, err := CheckWordlist(f, hashes)
+	if err != nil {
+		logrus.Errorf("Unable to read wordlist: %v", err)
+		os.Exit(1)
+	}
+
+	res.Print(os.Stdout)
+}
+
+// LoadPasswordHashes reads MD5 or SHA-1 password hashes from r, one per line. Each hash may be followed by
+// the plain text password, separated by a colon or tab, which is ignored. Empty lines and lines starting
+// with '#' are skipped.
+func LoadPasswordHashes(r io.Reader) (*PasswordHashes, error) {
+	ph := &PasswordHashes{Counts: make(map[string]int)}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash := strings.ToLower(strings.FieldsFunc(text, func(r rune) bool { return r == ':' || r == '\t' })[0])
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("invalid hash in line %d: %s", line, hash)
+		}
+
+		switch len(hash) {
+		case md5.Size * 2:
+			ph.MD5 = true
+		case sha1.Size * 2:
+			ph.SHA1 = true
+		default:
+			return nil, fmt.Errorf("unsupported hash in line %d: %s", line, hash)
+		}
+
+		ph.Counts[hash]++
+	}
+
+	return ph, scanner.Err()
+}
+
+// CheckWordlist reads a wordlist from r and looks up the MD5 and SHA-1 hash of each entry in hashes.
+func CheckWordlist(r io.Reader, hashes *PasswordHashes) (*CheckResult, error) {
+	res := &CheckResult{
+		Unique: len(hashes.Counts),
+		Found:  make(map[string]bool),
+	}
+
+	for _, c := range hashes.Counts {
+		res.Passwords += c
+	}
+
+	lookup := func(hash string) {
+		if c, ok := hashes.Counts[hash]; ok && !res.Found[hash] {
+			res.Found[hash] = true
+			res.Hits += c
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		entry := scanner.Bytes()
+		res.Entries++
+
+		if hashes.MD5 {
+			sum := md5.Sum(entry)
+			lookup(hex.EncodeToString(sum[:]))
+		}
+
+		if hashes.SHA1 {
+			sum := sha1.Sum(entry)
+			lookup(hex.EncodeToString(sum[:]))
+		}
+	}
+
+	return res, scanner.Err()
+}
+
+// Print writes the result in human readable form to w. The hit rate counts duplicate passwords, the
+// coverage counts unique passwords only.
+func (res *CheckResult) Print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Wordlist entries:\t%d\t\n", res.Entries)
+	fmt.Fprintf(tw, "Passwords:\t%d\t\n", res.Passwords)
+	fmt.Fprintf(tw, "Unique passwords:\t%d\t\n", res.Unique)
+	fmt.Fprintf(tw, "Passwords found:\t%d\t\n", res.Hits)
+	fmt.Fprintf(tw, "Unique passwords found:\t%d\t\n", len(res.Found))
+	fmt.Fprintf(tw, "Hit rate:\t%.2f %%\t\n", percent(res.Hits, res.Passwords))
+	fmt.Fprintf(tw, "Coverage:\t%.2f %%\t\n", percent(len(res.Found), res.Unique))
+
+	tw.Flush()
+}
+
+// percent returns n as a percentage of total, or 0 if total is 0.
+func percent(n int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return 100 * float64(n) / float64(total)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CheckpointOptions are the options that affect how names are counted. A checkpoint can only be resumed with
+// the same values, as the counts would mix names extracted in different ways otherwise.
+var CheckpointOptions = []string{
+	"language", "languages-file", "name-order", "name-separator", "namespace",
+	"firstname-tokens", "words-per-name", "emit-all-parts", "split-camel-case", "use-title-fallback",
+	"exclude-redirects", "redirect-follow", "stopwords-file", "name-transform", "charset",
+	"count", "combine",
+}
+
+// Checkpoint holds the state of an interrupted run: the names counted so far, the number of pages processed
+// in each dump, and the options they were counted with.
+type Checkpoint struct {
+	Sources    []CheckpointSource `json:"sources"`    // Dumps by language
+	Options    map[string]string  `json:"options"`    // Values of CheckpointOptions
+	Firstnames map[string]int     `json:"firstnames"` // Counts of first names
+	Combined   map[string]int     `json:"combined"`   // Counts of combined first and last names
+}
+
+// CheckpointSource identifies a dump and the number of pages processed in it.
+type CheckpointSource struct {
+	Language     string    `json:"language"`       // Language code
+	Name         string    `json:"name"`           // Path or URL of the dump
+	Size         int64     `json:"size"`           // Size in bytes (-1 if unknown)
+	ETag         string    `json:"etag,omitempty"` // Entity tag of the download
+	LastModified time.Time `json:"last-modified"`  // Time of last modification
+	Pages        int       `json:"pages"`          // Number of pages processed
+}
+
+// NewCheckpointSource returns the checkpoint source for the dump of the given language.
+func NewCheckpointSource(language string, info DumpInfo) CheckpointSource {
+	return CheckpointSource{
+		Language:     language,
+		Name:         info.Name,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		LastModified: info.LastModified.UTC(),
+	}
+}
+
+// Matches returns true if cs and other describe the identical dump.
+func (cs CheckpointSource) Matches(other CheckpointSource) bool {
+	return cs.Language == other.Language &&
+		cs.Name == other.Name &&
+		cs.Size == other.Size &&
+		cs.ETag == other.ETag &&
+		cs.LastModified.Equal(other.LastModified)
+}
+
+// ChangedOptions returns the names of all CheckpointOptions whose values differ from the ones the checkpoint
+// was saved with.
+func (cp *Checkpoint) ChangedOptions(options map[string]string) []string {
+	var changed []string
+
+	for _, name := range CheckpointOptions {
+		if saved, ok := cp.Options[name]; !ok || saved != options[name] {
+			changed = append(changed, name)
+		}
+	}
+
+	return changed
+}
+
+// Source returns the source of the given language, or nil if there is none.
+func (cp *Checkpoint) Source(language string) *CheckpointSource {
+	for i := range cp.Sources {
+		if cp.Sources[i].Language == language {
+			return &cp.Sources[i]
+		}
+	}
+
+	return nil
+}
+
+// LoadCheckpoint reads the checkpoint from the given file. It returns nil if the file does not exist.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read checkpoint: %w", err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("unable to decode checkpoint: %w", err)
+	}
+
+	return &cp, nil
+}
+
+// Save writes the checkpoint to the given file. A temporary file is renamed afterwards, so that an interrupted
+// write never leaves a corrupt checkpoint behind.
+func (cp *Checkpoint) Save(path string) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("unable to encode checkpoint: %w", err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to create checkpoint: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write checkpoint: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write checkpoint: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// checkpointOptions returns the values of all CheckpointOptions, with the given ones changed.
+func checkpointOptions(changes map[string]string) map[string]string {
+	options := make(map[string]string, len(CheckpointOptions))
+	for _, name := range CheckpointOptions {
+		options[name] = ""
+	}
+
+	for name, value := range changes {
+		options[name] = value
+	}
+
+	return options
+}
+
+func TestCheckpointChangedOptions(t *testing.T) {
+	cp := &Checkpoint{Options: checkpointOptions(map[string]string{"count": "2", "combine": "true"})}
+
+	tests := []struct {
+		name    string
+		options map[string]string
+		want    []string
+	}{
+		{"unchanged", checkpointOptions(map[string]string{"count": "2", "combine": "true"}), nil},
+		{"count", checkpointOptions(map[string]string{"count": "3", "combine": "true"}), []string{"count"}},
+		{"multiple", checkpointOptions(map[string]string{"namespace": "1", "count": "2"}), []string{"namespace", "combine"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cp.ChangedOptions(tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Checkpoints saved without options cannot be resumed
+	if got := (&Checkpoint{}).ChangedOptions(checkpointOptions(nil)); len(got) != len(CheckpointOptions) {
+		t.Errorf("got %q, want all options", got)
+	}
+}
+
+func TestCheckpointSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "names-wordlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "names.checkpoint")
+
+	cp := &Checkpoint{
+		Sources:    []CheckpointSource{{Language: "de", Name: "dewiki.xml", Size: 42, Pages: 3}},
+		Options:    checkpointOptions(map[string]string{"stopwords-file": "stopwords.txt"}),
+		Firstnames: map[string]int{"John": 2},
+		Combined:   map[string]int{"John Doe": 1},
+	}
+
+	if err := cp.Save(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded, cp) {
+		t.Errorf("got %+v, want %+v", loaded, cp)
+	}
+}
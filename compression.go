@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Magic bytes at the start of compressed files.
+var compressionMagic = []struct {
+	compression string
+	magic       []byte
+}{
+	{"bzip2", []byte("BZh")},
+	{"gzip", []byte{0x1f, 0x8b}},
+	{"xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+	{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd}},
+}
+
+// DetectCompression returns the compression of a dump based on the extension of its name, or an empty string
+// if the extension is unknown.
+func DetectCompression(name string) string {
+	switch {
+	case strings.HasSuffix(name, ".bz2"):
+		return "bzip2"
+	case strings.HasSuffix(name, ".gz"):
+		return "gzip"
+	case strings.HasSuffix(name, ".xz"):
+		return "xz"
+	case strings.HasSuffix(name, ".zst"):
+		return "zstd"
+	case strings.HasSuffix(name, ".xml"):
+		return "none"
+	default:
+		return ""
+	}
+}
+
+// CompressionExtension returns the file extension of the given compression (i.e. ".gz" for "gzip"), or an empty
+// string for "none".
+func CompressionExtension(compression string) string {
+	switch compression {
+	case "bzip2":
+		return ".bz2"
+	case "gzip":
+		return ".gz"
+	case "xz":
+		return ".xz"
+	case "zstd":
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// SniffCompression returns the compression of a dump based on its first bytes, or an empty string if the
+// bytes don't match any known compression.
+func SniffCompression(head []byte) string {
+	for _, cm := range compressionMagic {
+		if bytes.HasPrefix(head, cm.magic) {
+			return cm.compression
+		}
+	}
+
+	return ""
+}
+
+// CheckCompression peeks at the first bytes of r and returns an error if they don't start a stream of the
+// expected compression of a dump (for "auto", any known compression or else the one of the extension of name).
+// This catches i.e. HTML error pages served instead of the dump.
+func CheckCompression(r *bufio.Reader, compression string, name string) error {
+	head, _ := r.Peek(8)
+	sniffed := SniffCompression(head)
+
+	if compression == "auto" {
+		if sniffed != "" {
+			return nil
+		}
+
+		compression = DetectCompression(name)
+	}
+
+	if compression == "" || compression == "none" || sniffed == compression {
+		return nil
+	}
+
+	return fmt.Errorf("content is not a %s stream (starts with %q)", compression, head)
+}
+
+// NewDecompressReader returns a reader that decompresses r using the given compression ("none", "bzip2",
+// "gzip", "xz", or "zstd"). For "auto", the compression is sniffed from the first bytes of r and falls back
+// to the extension of name. With more than one worker, bzip2 streams are decompressed in parallel.
+//
+// Note that compress/bzip2 continues reading when a stream is followed by another one, so multi-stream files
+// as created by pbzip2 (or the "multistream" dumps of Wikipedia) are decompressed completely.
+func NewDecompressReader(r io.Reader, compression string, name string, workers int) (io.ReadCloser, error) {
+	if compression == "auto" {
+		br := bufio.NewReader(r)
+		head, _ := br.Peek(8)
+
+		compression = SniffCompression(head)
+		if compression == "" {
+			compression = DetectCompression(name)
+		}
+
+		if compression == "" {
+			compression = "none"
+		}
+
+		r = br
+	}
+
+	switch compression {
+	case "none":
+		return ioutil.NopCloser(r), nil
+	case "bzip2":
+		if workers > 1 {
+			return NewParallelBzip2Reader(r, workers), nil
+		}
+
+		return ioutil.NopCloser(bzip2.NewReader(r)), nil
+	case "gzip":
+		return gzip.NewReader(r)
+	case "xz":
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return ioutil.NopCloser(xr), nil
+	case "zstd":
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unknown compression: %s", compression)
+	}
+}
+
+// OutputCompression resolves the compression of an output written to name. With "auto", the compression is
+// chosen according to the extension of name ("gzip" or "zstd", or "none" for any other extension).
+func OutputCompression(compression string, name string) string {
+	if compression != "auto" {
+		return compression
+	}
+
+	switch c := DetectCompression(name); c {
+	case "gzip", "zstd":
+		return c
+	default:
+		return "none"
+	}
+}
+
+// NewCompressWriter returns a writer compressing to w using the given compression ("auto", "none", "gzip", or
+// "zstd") and level (0 for the default level). With "auto", the compression is chosen according to the
+// extension of name. Closing the writer flushes it, but does not close w.
+func NewCompressWriter(w io.Writer, compression string, level int, name string) (io.WriteCloser, error) {
+	switch OutputCompression(compression, name) {
+	case "none":
+		return nopWriteCloser{w}, nil
+	case "gzip":
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+
+		return gzip.NewWriterLevel(w, level)
+	case "zstd":
+		encLevel := zstd.SpeedDefault
+		if level != 0 {
+			encLevel = zstd.EncoderLevelFromZstd(level)
+		}
+
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(encLevel))
+	default:
+		return nil, fmt.Errorf("unknown compression: %s", compression)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// MachineFlags holds the flags with defaults that depend on the machine. They are written commented out, so a
+// config file shared between machines keeps the default of each.
+var MachineFlags = map[string]bool{"decompress-workers": true}
+
+// configGenerate is called for the "config-generate" sub command.
+func configGenerate(cmd *cobra.Command, args []string) {
+	// Open output file
+	path := "-"
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	out, err := CreateFile(path)
+	if err != nil {
+		logrus.Errorf("Unable to create config file: %v", err)
+		os.Exit(1)
+	}
+
+	defer out.Close()
+
+	// Write config
+	if err := WriteConfig(out, cmd.Root().Flags()); err != nil {
+		logrus.Errorf("Unable to write config file: %v", err)
+		os.Exit(1)
+	}
+}
+
+// WriteConfig writes a commented YAML config file to w that contains all flags with their default values,
+// skipping deprecated flags.
+func WriteConfig(w io.Writer, flags *pflag.FlagSet) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "# Configuration file for names-wordlist.")
+	fmt.Fprintln(bw, "#")
+	fmt.Fprintln(bw, "# Place this file as config.yaml in /etc/names-wordlist, $HOME/.config/names-wordlist, or the")
+	fmt.Fprintln(bw, "# current working directory. Command line flags and NAMES_WORDLIST_* environment variables take")
+	fmt.Fprintln(bw, "# precedence over values in this file.")
+
+	var err error
+
+	flags.VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Name == "help" || f.Name == "version" || f.Name == "config" || f.Deprecated != "" {
+			return
+		}
+
+		// Convert default value to its native type
+		var value interface{}
+
+		value, err = configValue(flags, f)
+		if err != nil {
+			return
+		}
+
+		// Write entry
+		var entry []byte
+
+		entry, err = yaml.Marshal(yaml.MapSlice{{Key: f.Name, Value: value}})
+		if err != nil {
+			return
+		}
+
+		fmt.Fprintln(bw)
+		fmt.Fprintf(bw, "# %s\n", strings.ToUpper(f.Usage[:1])+f.Usage[1:])
+
+		if MachineFlags[f.Name] {
+			fmt.Fprintln(bw, "# (default depends on the machine)")
+			entry = []byte("#" + strings.ReplaceAll(strings.TrimSuffix(string(entry), "\n"), "\n", "\n#") + "\n")
+		}
+
+		bw.Write(entry)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "# Named profiles override the options above when selected with --profile")
+	fmt.Fprintln(bw, "#profiles:")
+	fmt.Fprintln(bw, "#  quick:")
+	fmt.Fprintln(bw, "#    digits: 2")
+	fmt.Fprintln(bw, "#    special-chars: \"!\"")
+
+	return bw.Flush()
+}
+
+// configValue returns the default value of the flag f in its native type.
+func configValue(flags *pflag.FlagSet, f *pflag.Flag) (interface{}, error) {
+	switch f.Value.Type() {
+	case "bool":
+		return strconv.ParseBool(f.DefValue)
+	case "int":
+		return strconv.Atoi(f.DefValue)
+	case "float64":
+		return strconv.ParseFloat(f.DefValue, 64)
+	case "duration":
+		d, err := time.ParseDuration(f.DefValue)
+		return d.String(), err
+	case "stringSlice":
+		return flags.GetStringSlice(f.Name)
+	default:
+		return f.DefValue, nil
+	}
+}
+
+// ApplyProfile applies all options of the profile with the given name (from the "profiles" section of the
+// config file). Flags given explicitly on the command line take precedence.
+func ApplyProfile(name string, flags *pflag.FlagSet) error {
+	profile := viper.Sub("profiles." + name)
+	if profile == nil {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+
+	for _, key := range profile.AllKeys() {
+		if f := flags.Lookup(key); f != nil && f.Changed {
+			continue
+		}
+
+		viper.Set(key, profile.Get(key))
+	}
+
+	return nil
+}
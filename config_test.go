@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+func TestWriteConfig(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Int("digits", 4, "append up to N digits")
+	flags.StringSlice("case", wordlist.DefaultCases, "write names in these case variants")
+	flags.Int("decompress-workers", 12, "decompress with N workers in parallel")
+	flags.Bool("capitalize-first-only", false, "capitalize only the first letter")
+	flags.MarkDeprecated("capitalize-first-only", "use --title-mode single instead")
+
+	var buf bytes.Buffer
+	if err := WriteConfig(&buf, flags); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	config := buf.String()
+
+	if strings.Contains(config, "capitalize-first-only") {
+		t.Error("deprecated flag written")
+	}
+
+	if !strings.Contains(config, "\n#decompress-workers: 12\n") {
+		t.Errorf("machine dependent flag not commented out:\n%s", config)
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(buf.Bytes(), &values); err != nil {
+		t.Fatalf("invalid YAML: %v", err)
+	}
+
+	if len(values) != 2 || values["digits"] != 4 || len(values["case"].([]interface{})) != len(wordlist.DefaultCases) {
+		t.Errorf("got %v, want digits and case", values)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+)
+
+// CSVParser extracts names from a single column of a CSV or TSV file.
+type CSVParser struct {
+	Column    int                // Column holding the names (1-indexed)
+	Delimiter rune               // Field delimiter
+	Header    bool               // Skip the first record
+	Stopwords wordlist.Stopwords // Tokens that are skipped when picking first names
+	Joined    bool               // Keep all of multiple first names (joined by space) instead of the first one
+	Words     int                // Count up to this many of multiple first names on their own (-1 for all, ignored if joined)
+
+	Transforms wordlist.NameTransforms // Applied to names before splitting them (nil to disable)
+
+	Names int // Number of first names extracted so far
+}
+
+// CSVDelimiter returns the field delimiter for a file based on the extension of its name.
+func CSVDelimiter(name string) rune {
+	if strings.HasSuffix(name, ".tsv") || strings.HasSuffix(name, ".tab") {
+		return '\t'
+	}
+
+	return ','
+}
+
+// Parse reads records from r and calls emit for each first name found.
+func (cp *CSVParser) Parse(r io.Reader, emit func(wordlist.Name)) error {
+	if cp.Column < 1 {
+		return fmt.Errorf("invalid name column: %d", cp.Column)
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma = cp.Delimiter
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	cr.ReuseRecord = true
+
+	for line := 0; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("unable to read record: %w", err)
+		}
+
+		// Skip header and short records
+		if (cp.Header && line == 0) || len(record) < cp.Column {
+			continue
+		}
+
+		for _, firstname := range wordlist.PickFirstnames(strings.TrimSpace(cp.Transforms.Apply(record[cp.Column-1])), cp.Stopwords, cp.Joined, cp.Words) {
+			cp.Names++
+			emit(wordlist.Name{First: firstname})
+		}
+	}
+}
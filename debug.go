@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+)
+
+// debugEscaper escapes line breaks and tabs in fields of debug lines.
+var debugEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r", "\t", "\\t")
+
+// DebugWriter writes lines of tab separated fields for debugging extraction, escaping line breaks and tabs
+// within fields. It may be used by multiple parsers at once.
+type DebugWriter struct {
+	mu sync.Mutex     // Serializes writes
+	bw *bufio.Writer  // Buffered output
+	wc io.WriteCloser // Underlying file
+}
+
+// CreateDebugFile creates a debug file at path (or writes to stdout for "-").
+func CreateDebugFile(path string) (*DebugWriter, error) {
+	wc, err := CreateFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DebugWriter{bw: bufio.NewWriter(wc), wc: wc}, nil
+}
+
+// WriteLine writes the given fields as a single line.
+func (dw *DebugWriter) WriteLine(fields ...string) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+
+	for i, f := range fields {
+		if i > 0 {
+			dw.bw.WriteByte('\t')
+		}
+
+		dw.bw.WriteString(debugEscaper.Replace(f))
+	}
+
+	dw.bw.WriteByte('\n')
+}
+
+// Close flushes and closes the debug file.
+func (dw *DebugWriter) Close() error {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+
+	if err := dw.bw.Flush(); err != nil {
+		dw.wc.Close()
+		return err
+	}
+
+	return dw.wc.Close()
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// DiffResult holds the number of entries in each set of a wordlist comparison.
+type DiffResult struct {
+	OnlyA int // Entries only in the first wordlist
+	OnlyB int // Entries only in the second wordlist
+	Both  int // Entries in both wordlists
+}
+
+// diff is called for the "diff" sub command.
+func diff(cmd *cobra.Command, args []string) {
+	paths := make(map[string]string)
+	for _, name := range []string{"only-a", "only-b", "both"} {
+		paths[name], _ = cmd.Flags().GetString(name)
+	}
+
+	// Open output files (or write all sets to stdout, marked with a prefix)
+	writers := make(map[string]*bufio.Writer)
+	prefixes := map[string]string{"only-a": "< ", "only-b": "> ", "both": "= "}
+
+	stdout := paths["only-a"] == "" && paths["only-b"] == "" && paths["both"] == ""
+	if stdout {
+		w := bufio.NewWriter(os.Stdout)
+		for name := range paths {
+			writers[name] = w
+		}
+	} else {
+		for name, path := range paths {
+			if path == "" {
+				continue
+			}
+
+			f, err := CreateFile(path)
+			if err != nil {
+				logrus.Errorf("Unable to create output file: %v", err)
+				os.Exit(1)
+			}
+
+			defer f.Close()
+
+			writers[name] = bufio.NewWriter(f)
+			prefixes[name] = ""
+		}
+	}
+
+	write := func(name string, line string) {
+		if w := writers[name]; w != nil {
+			w.WriteString(prefixes[name] + line + "\n")
+		}
+	}
+
+	// Compare
+	res, err := DiffWordlists(args[0], args[1], write)
+	if err != nil {
+		logrus.Errorf("Unable to compare wordlists: %v", err)
+		os.Exit(1)
+	}
+
+	for _, w := range writers {
+		if err := w.Flush(); err != nil {
+			logrus.Errorf("Unable to write output file: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	logrus.Infof("%d entries only in %s, %d only in %s, %d in both", res.OnlyA, args[0], res.OnlyB, args[1], res.Both)
+}
+
+// DiffWordlists compares two wordlists and calls write with "only-a", "only-b", or "both" for each entry. Only
+// the first wordlist is held in memory; the second one is streamed (so duplicates only in it are written as
+// often as they occur), and the first one is read a second time to find the entries only in it.
+func DiffWordlists(pathA string, pathB string, write func(set string, line string)) (*DiffResult, error) {
+	res := &DiffResult{}
+
+	// Read first wordlist (false until seen in the second one)
+	seen := make(map[string]bool)
+
+	err := scanFile(pathA, func(line string) {
+		seen[line] = false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Stream second wordlist
+	err = scanFile(pathB, func(line string) {
+		found, ok := seen[line]
+		if !ok {
+			res.OnlyB++
+			write("only-b", line)
+		} else if !found {
+			seen[line] = true
+			res.Both++
+			write("both", line)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Stream first wordlist again
+	err = scanFile(pathA, func(line string) {
+		if found, ok := seen[line]; ok && !found {
+			delete(seen, line)
+			res.OnlyA++
+			write("only-a", line)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// scanFile calls fn for each line of the file at path.
+func scanFile(path string, fn func(line string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fn(scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("unable to read %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package main
+
+// FreeSpace returns the number of bytes available to unprivileged users on the file system holding path, or
+// false if it can't be determined (which is always the case on this platform).
+func FreeSpace(path string) (int64, bool) {
+	return 0, false
+}
@@ -0,0 +1,19 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package main
+
+import (
+	"syscall"
+)
+
+// FreeSpace returns the number of bytes available to unprivileged users on the file system holding path, or
+// false if it can't be determined.
+func FreeSpace(path string) (int64, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, false
+	}
+
+	return int64(st.Bavail) * int64(st.Bsize), true
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// DumpHead describes the dump of a single language as reported by its server.
+type DumpHead struct {
+	Code         string    // Language code
+	URL          string    // URL of the dump
+	Size         int64     // Size in bytes (-1 if unknown)
+	LastModified time.Time // Time of last modification (zero if unknown)
+	Err          error     // Error querying the dump
+}
+
+// dumps is called for the "dumps" sub command.
+func dumps(cmd *cobra.Command, args []string) {
+	caCert, _ := cmd.Flags().GetString("ca-cert")
+	insecure, _ := cmd.Flags().GetBool("insecure")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	// Load additional languages
+	if path, _ := cmd.Flags().GetString("languages-file"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			logrus.Errorf("Unable to open languages file: %v", err)
+			os.Exit(1)
+		}
+
+		err = wordlist.LoadLanguages(f)
+		f.Close()
+
+		if err != nil {
+			logrus.Errorf("Unable to load languages file: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	client, err := NewHTTPClient(caCert, insecure, 0)
+	if err != nil {
+		logrus.Errorf("Unable to set up HTTP client: %v", err)
+		os.Exit(1)
+	}
+
+	client.Timeout = timeout
+
+	// Query all dumps in parallel
+	codes := wordlist.LanguageCodes()
+	heads := make([]DumpHead, len(codes))
+
+	var wg sync.WaitGroup
+
+	for i, code := range codes {
+		wg.Add(1)
+
+		go func(i int, code string) {
+			defer wg.Done()
+			heads[i] = HeadDump(client, code, wordlist.Languages[code].DumpURL)
+		}(i, code)
+	}
+
+	wg.Wait()
+
+	for _, dh := range heads {
+		if dh.Err != nil {
+			logrus.Warnf("Unable to query dump of %s: %v", dh.Code, dh.Err)
+		}
+	}
+
+	PrintDumpHeads(os.Stdout, heads)
+}
+
+// HeadDump queries the size and time of last modification of the dump at url using a HEAD request.
+func HeadDump(client *http.Client, code string, url string) DumpHead {
+	dh := DumpHead{Code: code, URL: url, Size: -1}
+
+	resp, err := client.Head(url)
+	if err != nil {
+		dh.Err = err
+		return dh
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		dh.Err = fmt.Errorf("%s", resp.Status)
+		return dh
+	}
+
+	dh.Size = resp.ContentLength
+
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		dh.LastModified = t
+	}
+
+	return dh
+}
+
+// PrintDumpHeads writes a table of the given dumps to w (leaving out errors).
+func PrintDumpHeads(w io.Writer, heads []DumpHead) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Language\tURL\tSize\tLast modified\t\n")
+
+	for _, dh := range heads {
+		if dh.Err != nil {
+			fmt.Fprintf(tw, "%s\t%s\tunavailable\t\t\n", dh.Code, dh.URL)
+			continue
+		}
+
+		size, modified := "unknown", "unknown"
+		if dh.Size >= 0 {
+			size = FormatSize(dh.Size)
+		}
+
+		if !dh.LastModified.IsZero() {
+			modified = dh.LastModified.Format("2006-01-02 15:04")
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n", dh.Code, dh.URL, size, modified)
+	}
+
+	tw.Flush()
+}
+
+// FormatSize formats a size in bytes using binary units (i.e. "6.71 GiB").
+func FormatSize(n int64) string {
+	const unit = 1 << 10
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	v, exp := float64(n)/unit, 0
+	for v >= unit && exp < 3 {
+		v /= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.2f %ciB", v, "KMGT"[exp])
+}
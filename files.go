@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+	"github.com/sirupsen/logrus"
+)
+
+// nopWriteCloser wraps a writer that must not be closed (i.e. stdout).
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// CreateFile creates or truncates the file at path. If path is "-", stdout is returned instead.
+func CreateFile(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+
+	return OpenOutputFile(path, false)
+}
+
+// OpenOutputFile creates or truncates (or appends to) the file at path. Named pipes are opened for writing only,
+// which blocks until a reader (i.e. hashcat) opens the other end.
+func OpenOutputFile(path string, appending bool) (*os.File, error) {
+	if IsNamedPipe(path) {
+		logrus.Infof("Waiting for a reader to open named pipe %s", path)
+		return os.OpenFile(path, os.O_WRONLY, 0)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appending {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	return os.OpenFile(path, flags, 0666)
+}
+
+// IsNamedPipe returns true if path is an existing named pipe (FIFO).
+func IsNamedPipe(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode()&os.ModeNamedPipe != 0
+}
+
+// compressedFile is a file written through a compressing writer.
+type compressedFile struct {
+	io.WriteCloser          // Compressing writer
+	file           *os.File // Underlying file
+}
+
+// Close flushes the compressing writer and closes the file.
+func (cf *compressedFile) Close() error {
+	err := cf.WriteCloser.Close()
+	if ferr := cf.file.Close(); err == nil {
+		err = ferr
+	}
+
+	return err
+}
+
+// NameFiles creates the files holding the entries of single names in a directory.
+type NameFiles struct {
+	Dir         string          // Directory of the files
+	Compression string          // Compression of the files ("none", "gzip", or "zstd")
+	used        map[string]bool // File names created so far (lower case)
+}
+
+// NewNameFiles returns a NameFiles creating files in dir, compressed using the given compression.
+func NewNameFiles(dir string, compression string) *NameFiles {
+	return &NameFiles{Dir: dir, Compression: compression, used: make(map[string]bool)}
+}
+
+// Create creates the file holding the entries of a single name, named after the name (i.e. "Anna.txt" or
+// "Anna Schmidt.txt.gz"). Names mapping to a file created before (i.e. "Anna/Maria" and "Anna_Maria", or
+// names differing in case only on case-insensitive file systems) get a number appended ("Anna_Maria (2).txt").
+func (nf *NameFiles) Create(name wordlist.Name) (io.WriteCloser, error) {
+	base := strings.TrimSpace(name.First + " " + name.Last)
+	base = strings.NewReplacer("/", "_", "\\", "_", "\x00", "_").Replace(base)
+
+	file := base
+	for i := 2; nf.used[strings.ToLower(file)]; i++ {
+		file = fmt.Sprintf("%s (%d)", base, i)
+	}
+
+	nf.used[strings.ToLower(file)] = true
+
+	f, err := os.Create(filepath.Join(nf.Dir, file+".txt"+CompressionExtension(nf.Compression)))
+	if err != nil {
+		return nil, err
+	}
+
+	cw, err := NewCompressWriter(f, nf.Compression, 0, "")
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &compressedFile{WriteCloser: cw, file: f}, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+)
+
+func TestNameFilesCollisions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "names-wordlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	nf := NewNameFiles(dir, "none")
+
+	for _, name := range []wordlist.Name{{First: "Anna/Maria"}, {First: "Anna_Maria"}, {First: "anna_maria"}, {First: "Anna", Last: "Schmidt"}} {
+		f, err := nf.Create(name)
+		if err != nil {
+			t.Fatalf("unable to create file for %v: %v", name, err)
+		}
+
+		if _, err := f.Write([]byte(name.First + "\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, filepath.Base(f)+": "+string(data))
+	}
+
+	sort.Strings(got)
+
+	want := []string{
+		"Anna Schmidt.txt: Anna\n",
+		"Anna_Maria (2).txt: Anna_Maria\n",
+		"Anna_Maria.txt: Anna/Maria\n",
+		"anna_maria (3).txt: anna_maria\n",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %q, want %q", got[i], want[i])
+		}
+	}
+}
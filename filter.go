@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// WordlistFilter decides which entries of a wordlist are kept.
+type WordlistFilter struct {
+	MinLength int              // Minimum length in characters (0 for no limit)
+	MaxLength int              // Maximum length in characters (0 for no limit)
+	Include   []*regexp.Regexp // Keep only entries matching any of these (empty to keep all)
+	Exclude   []*regexp.Regexp // Drop entries matching any of these
+}
+
+// filter is called for the "filter" sub command.
+func filter(cmd *cobra.Command, args []string) {
+	output, _ := cmd.Flags().GetString("output")
+	minLength, _ := cmd.Flags().GetInt("min-length")
+	maxLength, _ := cmd.Flags().GetInt("max-length")
+	include, _ := cmd.Flags().GetStringArray("include-pattern")
+	exclude, _ := cmd.Flags().GetStringArray("exclude-pattern")
+
+	// Compile filter
+	wf := &WordlistFilter{MinLength: minLength, MaxLength: maxLength}
+
+	var err error
+
+	if wf.Include, err = compilePatterns(include); err != nil {
+		logrus.Errorf("Invalid include pattern: %v", err)
+		os.Exit(1)
+	}
+
+	if wf.Exclude, err = compilePatterns(exclude); err != nil {
+		logrus.Errorf("Invalid exclude pattern: %v", err)
+		os.Exit(1)
+	}
+
+	// Open input and output file
+	f, err := os.Open(args[0])
+	if err != nil {
+		logrus.Errorf("Unable to open wordlist: %v", err)
+		os.Exit(1)
+	}
+
+	defer f.Close()
+
+	out, err := CreateFile(output)
+	if err != nil {
+		logrus.Errorf("Unable to create output file: %v", err)
+		os.Exit(1)
+	}
+
+	defer out.Close()
+
+	w := bufio.NewWriter(out)
+
+	// Filter line by line
+	var read, kept int
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		read++
+
+		if wf.Keep(line) {
+			w.WriteString(line + "\n")
+			kept++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		logrus.Errorf("Unable to read wordlist: %v", err)
+		os.Exit(1)
+	}
+
+	if err := w.Flush(); err != nil {
+		logrus.Errorf("Unable to write output file: %v", err)
+		os.Exit(1)
+	}
+
+	logrus.Infof("Kept %d of %d lines", kept, read)
+}
+
+// Keep returns true if the entry passes all filters.
+func (wf *WordlistFilter) Keep(entry string) bool {
+	n := utf8.RuneCountInString(entry)
+	if n < wf.MinLength || (wf.MaxLength > 0 && n > wf.MaxLength) {
+		return false
+	}
+
+	for _, re := range wf.Exclude {
+		if re.MatchString(entry) {
+			return false
+		}
+	}
+
+	if len(wf.Include) == 0 {
+		return true
+	}
+
+	for _, re := range wf.Include {
+		if re.MatchString(entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compilePatterns compiles the given regular expressions.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+
+		res = append(res, re)
+	}
+
+	return res, nil
+}
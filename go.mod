@@ -1,13 +1,38 @@
 module github.com/crissyfield/names-wordlist
 
-go 1.13
+go 1.18
 
 require (
+	github.com/VividCortex/ewma v1.1.1
 	github.com/fatih/color v1.7.0
-	github.com/mattn/go-colorable v0.1.4 // indirect
-	github.com/mattn/go-isatty v0.0.11 // indirect
+	github.com/fsnotify/fsnotify v1.4.7
+	github.com/klauspost/compress v1.10.10
+	github.com/mattn/go-isatty v0.0.11
+	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.5
+	github.com/spf13/pflag v1.0.3
 	github.com/spf13/viper v1.6.1
+	github.com/ulikunitz/xz v0.5.8
 	github.com/vbauerster/mpb/v4 v4.11.1
+	golang.org/x/text v0.3.0
+	golang.org/x/time v0.0.0-20190921001708-c4c64cad1fd0
+	gopkg.in/yaml.v2 v2.2.4
+)
+
+require (
+	github.com/hashicorp/hcl v1.0.0 // indirect
+	github.com/inconshreveable/mousetrap v1.0.0 // indirect
+	github.com/konsorten/go-windows-terminal-sequences v1.0.1 // indirect
+	github.com/magiconair/properties v1.8.1 // indirect
+	github.com/mattn/go-colorable v0.1.4 // indirect
+	github.com/mitchellh/mapstructure v1.1.2 // indirect
+	github.com/pelletier/go-toml v1.2.0 // indirect
+	github.com/spf13/afero v1.1.2 // indirect
+	github.com/spf13/cast v1.3.0 // indirect
+	github.com/spf13/jwalterweatherman v1.0.0 // indirect
+	github.com/subosito/gotenv v1.2.0 // indirect
+	golang.org/x/crypto v0.0.0-20191112222119-e1110fd1c708 // indirect
+	golang.org/x/sys v0.0.0-20191113165036-4c7a9d0fe056 // indirect
+	gopkg.in/ini.v1 v1.51.0 // indirect
 )
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NameCount is a name together with its number of occurrences.
+type NameCount struct {
+	Name  string // Name
+	Count int    // Number of occurrences
+}
+
+// CounterShards is the number of shards of a Counter.
+const CounterShards = 64
+
+// Counter counts the occurrences of names and is safe for concurrent use. Names are spread over shards by their
+// hash, each with its own lock, so that parsers running in parallel rarely wait for each other. The zero value
+// is an empty counter.
+type Counter struct {
+	shards [CounterShards]counterShard // Shards by hash of the name
+}
+
+// counterShard holds the counts of a part of the names of a Counter.
+type counterShard struct {
+	mu     sync.Mutex     // Guards counts
+	counts map[string]int // Count by name
+}
+
+// shard returns the shard of name (using the FNV-1a hash).
+func (c *Counter) shard(name string) *counterShard {
+	h := uint32(2166136261)
+	for i := 0; i < len(name); i++ {
+		h ^= uint32(name[i])
+		h *= 16777619
+	}
+
+	return &c.shards[h%CounterShards]
+}
+
+// Add increments the count of name by one and returns the new count.
+func (c *Counter) Add(name string) int {
+	sh := c.shard(name)
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if sh.counts == nil {
+		sh.counts = make(map[string]int)
+	}
+
+	sh.counts[name]++
+
+	return sh.counts[name]
+}
+
+// Load sets the counts of all names in hist.
+func (c *Counter) Load(hist map[string]int) {
+	for name, count := range hist {
+		sh := c.shard(name)
+
+		sh.mu.Lock()
+		if sh.counts == nil {
+			sh.counts = make(map[string]int)
+		}
+
+		sh.counts[name] = count
+		sh.mu.Unlock()
+	}
+}
+
+// Snapshot returns the current counts of all names, merged from all shards.
+func (c *Counter) Snapshot() map[string]int {
+	hist := make(map[string]int)
+
+	for i := range c.shards {
+		sh := &c.shards[i]
+
+		sh.mu.Lock()
+		for name, count := range sh.counts {
+			hist[name] = count
+		}
+		sh.mu.Unlock()
+	}
+
+	return hist
+}
+
+// SortedHistogram returns all names in hist with at least min and at most max occurrences (0 for no upper
+// bound), sorted by descending count and ascending name.
+func SortedHistogram(hist map[string]int, min int, max int) []NameCount {
+	var ncs []NameCount
+	for name, count := range hist {
+		if count >= min && (max <= 0 || count <= max) {
+			ncs = append(ncs, NameCount{Name: name, Count: count})
+		}
+	}
+
+	sort.Slice(ncs, func(i, j int) bool {
+		if ncs[i].Count != ncs[j].Count {
+			return ncs[i].Count > ncs[j].Count
+		}
+
+		return ncs[i].Name < ncs[j].Name
+	})
+
+	return ncs
+}
+
+// CountPercentile returns the count at the given percentile (0 to 100) of all counts in hist, so that names
+// with at least this count are the most frequent ones (i.e. the top 10 % for 90). It returns 0 for an empty
+// histogram.
+func CountPercentile(hist map[string]int, p float64) int {
+	if len(hist) == 0 {
+		return 0
+	}
+
+	counts := make([]int, 0, len(hist))
+	for _, count := range hist {
+		counts = append(counts, count)
+	}
+
+	sort.Ints(counts)
+
+	// First count after the lower p percent
+	i := int(math.Floor(p / 100 * float64(len(counts))))
+	if i >= len(counts) {
+		i = len(counts) - 1
+	}
+
+	return counts[i]
+}
+
+// ReadFrequencies reads names with their counts as written by WriteFrequencies (in either format, or with name
+// and count separated by a tab) from r.
+func ReadFrequencies(r io.Reader) (map[string]int, error) {
+	hist := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		// Plain ("count name")
+		if i := strings.IndexByte(text, ' '); i > 0 {
+			if count, err := strconv.Atoi(text[:i]); err == nil {
+				hist[strings.TrimSpace(text[i+1:])] += count
+				continue
+			}
+		}
+
+		// CSV ("name,count") or TSV
+		i := strings.LastIndexAny(text, ",\t")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid frequency in line %d: %s", line, text)
+		}
+
+		count, err := strconv.Atoi(strings.TrimSpace(text[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid count in line %d: %s", line, text)
+		}
+
+		hist[strings.TrimSpace(text[:i])] += count
+	}
+
+	return hist, scanner.Err()
+}
+
+// WriteFrequencies writes the names with their counts to w, one per line. The format is either "plain"
+// ("count name") or "csv" ("name,count").
+func WriteFrequencies(w io.Writer, ncs []NameCount, format string) error {
+	bw := bufio.NewWriter(w)
+
+	for _, nc := range ncs {
+		switch format {
+		case "plain":
+			fmt.Fprintf(bw, "%d %s\n", nc.Count, nc.Name)
+		case "csv":
+			fmt.Fprintf(bw, "%s,%d\n", nc.Name, nc.Count)
+		default:
+			return fmt.Errorf("unknown frequency format: %s", format)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// YearKey returns the key counting a birth year of the given name in a year histogram.
+func YearKey(name string, year string) string {
+	return name + "\t" + year
+}
+
+// YearsByName groups a year histogram (with keys created by YearKey) by name, returning the years of each name
+// sorted by descending count.
+func YearsByName(hist map[string]int) map[string][]string {
+	years := make(map[string][]string)
+
+	for _, nc := range SortedHistogram(hist, 1, 0) {
+		if i := strings.LastIndex(nc.Name, "\t"); i >= 0 {
+			years[nc.Name[:i]] = append(years[nc.Name[:i]], nc.Name[i+1:])
+		}
+	}
+
+	return years
+}
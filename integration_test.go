@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// integrationDump holds a person, a person with multiple first names, a talk page, a redirect, and a person
+// with an outdated revision.
+const integrationDump = `<mediawiki>
+<page><title>John Doe</title><ns>0</ns><id>1</id><revision><id>10</id><text>'''John Doe''' {{Personendaten
+|NAME=Doe, [[John Doe|John]]
+|GEBURTSDATUM=3. März 1975
+}}</text></revision></page>
+<page><title>Anna Maria Schmidt</title><ns>0</ns><id>2</id><revision><id>11</id><text>{{Personendaten
+|NAME=Schmidt, Anna Maria
+}}</text></revision></page>
+<page><title>Diskussion:John Doe</title><ns>1</ns><id>3</id><revision><id>12</id><text>{{Personendaten|NAME=Talk, Tom}}</text></revision></page>
+<page><title>Johnny Doe</title><ns>0</ns><id>4</id><redirect title="John Doe" /><revision><id>13</id><text>#WEITERLEITUNG [[John Doe]]</text></revision></page>
+<page><title>Jane Doe</title><ns>0</ns><id>5</id>
+<revision><id>14</id><text>{{Personendaten|NAME=Doe, Janet}}</text></revision>
+<revision><id>15</id><text>{{Personendaten|NAME=Doe, Jane}}</text></revision>
+</page>
+</mediawiki>`
+
+// TestMain runs the command itself instead of the tests when started by runCommand.
+func TestMain(m *testing.M) {
+	if args := os.Getenv("TEST_NAMES_WORDLIST_ARGS"); args != "" {
+		os.Args = append([]string{"names-wordlist"}, strings.Split(args, "\n")...)
+		main()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// runCommand runs the root command with the given arguments in a new process (as it exits on errors), and
+// returns its combined output.
+func runCommand(t *testing.T, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run", "^$")
+	cmd.Env = append(os.Environ(), "TEST_NAMES_WORDLIST_ARGS="+strings.Join(args, "\n"))
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("command failed: %v\n%s", err, out)
+	}
+
+	return string(out)
+}
+
+// runFailingCommand runs the root command like runCommand, but expects it to fail.
+func runFailingCommand(t *testing.T, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run", "^$")
+	cmd.Env = append(os.Environ(), "TEST_NAMES_WORDLIST_ARGS="+strings.Join(args, "\n"))
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("command succeeded unexpectedly\n%s", out)
+	}
+
+	return string(out)
+}
+
+// compressBzip2 compresses data with the bzip2 tool, skipping the test if it's not installed.
+func compressBzip2(t *testing.T, data string) []byte {
+	t.Helper()
+
+	if _, err := exec.LookPath("bzip2"); err != nil {
+		t.Skip("bzip2 not installed")
+	}
+
+	cmd := exec.Command("bzip2", "-c")
+	cmd.Stdin = strings.NewReader(data)
+
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("unable to compress dump: %v", err)
+	}
+
+	return out
+}
+
+func TestIntegrationDumpURL(t *testing.T) {
+	dump := compressBzip2(t, integrationDump)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "dewiki-latest-pages-articles.xml.bz2", time.Now(), bytes.NewReader(dump))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "names-wordlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "output.lst")
+
+	runCommand(t,
+		"--quiet", "--yes",
+		"--dump-url", srv.URL+"/dewiki-latest-pages-articles.xml.bz2",
+		"--digits", "1",
+		"--special-chars", "!",
+		output,
+	)
+
+	data, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatalf("unable to read output: %v", err)
+	}
+
+	entries := make(map[string]bool)
+	for _, e := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		entries[e] = true
+	}
+
+	// 3 names in 3 cases, each with 11 digit and 2 special character suffixes
+	if len(entries) != 3*3*11*2 {
+		t.Errorf("got %d entries, want %d", len(entries), 3*3*11*2)
+	}
+
+	for _, e := range []string{"john", "JOHN", "John", "john7", "John7!", "anna", "ANNA0!", "jane", "Jane9"} {
+		if !entries[e] {
+			t.Errorf("entry %q missing", e)
+		}
+	}
+
+	// Talk pages, outdated revisions, and second first names are skipped
+	for _, e := range []string{"tom", "janet", "maria", "annamaria"} {
+		if entries[e] {
+			t.Errorf("unexpected entry %q", e)
+		}
+	}
+}
+
+func TestIntegrationJoinedCharset(t *testing.T) {
+	dir, err := ioutil.TempDir("", "names-wordlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	dump := filepath.Join(dir, "dewiki.xml")
+	if err := ioutil.WriteFile(dump, []byte(integrationDump), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "output.lst")
+
+	runCommand(t,
+		"--quiet", "--yes",
+		"--dump-file", dump,
+		"--firstname-tokens", "joined",
+		"--charset", "ascii",
+		"--names-only",
+		"--case", "lower",
+		output,
+	)
+
+	data, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatalf("unable to read output: %v", err)
+	}
+
+	// Joined first names pass the charset, like single ones
+	want := "john\nannamaria\nanna_maria\nanna-maria\njane\n"
+	if got := string(data); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestIntegrationNameTransform(t *testing.T) {
+	dir, err := ioutil.TempDir("", "names-wordlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	dump := filepath.Join(dir, "dewiki.xml")
+	if err := ioutil.WriteFile(dump, []byte(`<mediawiki>
+<page><title>Anna-Maria Schmidt</title><ns>0</ns><id>1</id><revision><id>10</id><text>{{Personendaten|NAME=Schmidt, Anna-Maria}}</text></revision></page>
+<page><title>Anna Maria Meier</title><ns>0</ns><id>2</id><revision><id>11</id><text>{{Personendaten|NAME=Meier, Anna Maria}}</text></revision></page>
+<page><title>John Doe</title><ns>0</ns><id>3</id><revision><id>12</id><text>{{Personendaten|NAME=Doe, John}}</text></revision></page>
+</mediawiki>`), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "output.lst")
+
+	runCommand(t,
+		"--quiet", "--yes",
+		"--dump-file", dump,
+		"--name-transform", "-= ",
+		"--name-transform", "Anna Maria=Annamaria",
+		"--count", "2",
+		"--names-only",
+		"--case", "lower",
+		output,
+	)
+
+	data, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatalf("unable to read output: %v", err)
+	}
+
+	// Transforms see the whole value, so both spellings are counted as one name
+	if got, want := string(data), "annamaria\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestIntegrationQuietConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "names-wordlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	dump := filepath.Join(dir, "dewiki.xml")
+	if err := ioutil.WriteFile(dump, []byte(integrationDump), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	config := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(config, []byte("yes: true\nprofiles:\n  silent:\n    quiet: true\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	quiet := filepath.Join(dir, "quiet.yaml")
+	if err := ioutil.WriteFile(quiet, []byte("yes: true\nquiet: true\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "output.lst")
+
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{"flag", []string{"--quiet", "--yes"}, false},
+		{"config file", []string{"--config", quiet}, false},
+		{"profile", []string{"--config", config, "--profile", "silent"}, false},
+		{"not quiet", []string{"--config", config}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := runCommand(t, append(tt.args, "--dump-file", dump, output)...)
+
+			// Quiet set by a config file or profile suppresses the banner as well
+			if got := strings.Contains(out, "|_____|"); got != tt.want {
+				t.Errorf("got banner %v, want %v:\n%s", got, tt.want, out)
+			}
+		})
+	}
+}
+
+func TestIntegrationRequireMinNames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "names-wordlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	dump := filepath.Join(dir, "dewiki.xml")
+	if err := ioutil.WriteFile(dump, []byte(integrationDump), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runFailingCommand(t,
+		"--quiet", "--yes",
+		"--dump-file", dump,
+		"--combine",
+		"--count", "2",
+		filepath.Join(dir, "output.lst"),
+	)
+
+	// 3 first names and 3 combined names, each occuring once
+	if !strings.Contains(out, "6 names were extracted") {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
+
+func TestIntegrationCheckpointOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "names-wordlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	dump := filepath.Join(dir, "dewiki.xml")
+	if err := ioutil.WriteFile(dump, []byte(integrationDump), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	// Checkpoint of a run with --combine and --count 2
+	checkpoint := filepath.Join(dir, "names.checkpoint")
+	cp := &Checkpoint{Options: checkpointOptions(map[string]string{"combine": "true", "count": "2"})}
+
+	if err := cp.Save(checkpoint); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runFailingCommand(t,
+		"--quiet", "--yes",
+		"--dump-file", dump,
+		"--checkpoint", checkpoint,
+		"--count", "3",
+		filepath.Join(dir, "output.lst"),
+	)
+
+	// Among others, as the checkpoint has no other options
+	if !strings.Contains(out, "count, combine changed since checkpoint") {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// lookup is called for the "lookup" sub command.
+func lookup(cmd *cobra.Command, args []string) {
+	path, _ := cmd.Flags().GetString("histogram")
+	cnt, _ := cmd.Flags().GetInt("count")
+	digits, _ := cmd.Flags().GetInt("digits")
+	specialChars, _ := cmd.Flags().GetString("special-chars")
+	cases, _ := cmd.Flags().GetStringSlice("case")
+
+	for _, c := range cases {
+		if !wordlist.ValidCase(c) {
+			logrus.Errorf("Unknown case: %s", c)
+			os.Exit(1)
+		}
+	}
+
+	// Load histogram
+	f, err := os.Open(path)
+	if err != nil {
+		logrus.Errorf("Unable to open histogram: %v", err)
+		os.Exit(1)
+	}
+
+	hist, err := ReadFrequencies(f)
+	f.Close()
+
+	if err != nil {
+		logrus.Errorf("Unable to read histogram: %v", err)
+		os.Exit(1)
+	}
+
+	// Look up names and write their variants just like the output routine would
+	opts := &wordlist.OutputOptions{
+		Digits:       digits,
+		SpecialChars: specialChars,
+		LineEnding:   "\n",
+		Cases:        cases,
+	}
+
+	for _, arg := range args {
+		name, count := LookupName(hist, arg)
+		if count == 0 {
+			logrus.Warnf("%s does not occur in the histogram", arg)
+			continue
+		}
+
+		if count < cnt {
+			logrus.Warnf("%s occurs %d times, less than --count %d: not in wordlist", name, count, cnt)
+			continue
+		}
+
+		logrus.Infof("%s occurs %d times: in wordlist", name, count)
+
+		ch := make(chan wordlist.Name, 1)
+		ch <- wordlist.Name{First: name}
+		close(ch)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		wordlist.OutputRoutine(os.Stdout, opts, ch, &wordlist.OutputStats{}, &wg)
+	}
+}
+
+// LookupName returns the name in hist matching name and its count, or a count of 0 if there is none. If there
+// is no exact match, the most frequent of the names matching when ignoring case is returned (the first in
+// lexical order among equally frequent ones, i.e. "ANNA" before "Anna").
+func LookupName(hist map[string]int, name string) (string, int) {
+	if count, ok := hist[name]; ok {
+		return name, count
+	}
+
+	match, matchCount := name, 0
+
+	for n, count := range hist {
+		if !strings.EqualFold(n, name) {
+			continue
+		}
+
+		if count > matchCount || (count == matchCount && n < match) {
+			match, matchCount = n, count
+		}
+	}
+
+	return match, matchCount
+}
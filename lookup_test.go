@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestLookupName(t *testing.T) {
+	hist := map[string]int{"Anna": 5, "ANNA": 5, "anna": 2, "Maria": 3, "MARIA": 7, "Jan": 1}
+
+	tests := []struct {
+		name      string
+		wantName  string
+		wantCount int
+	}{
+		{"Anna", "Anna", 5},   // Exact match
+		{"anna", "anna", 2},   // Exact match, although less frequent
+		{"aNNa", "ANNA", 5},   // Equally frequent, first in lexical order
+		{"maria", "MARIA", 7}, // Most frequent
+		{"JAN", "Jan", 1},     // Only match
+		{"Peter", "Peter", 0}, // No match
+	}
+
+	for _, tt := range tests {
+		// Repeat, as map iteration order varies
+		for i := 0; i < 20; i++ {
+			if n, count := LookupName(hist, tt.name); n != tt.wantName || count != tt.wantCount {
+				t.Fatalf("LookupName(%q) = %q, %d, want %q, %d", tt.name, n, count, tt.wantName, tt.wantCount)
+			}
+		}
+	}
+}
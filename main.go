@@ -1,35 +1,39 @@
 package main
 
 import (
-	"compress/bzip2"
-	"encoding/xml"
+	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/VividCortex/ewma"
+	"github.com/crissyfield/names-wordlist/wordlist"
 	"github.com/fatih/color"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/vbauerster/mpb/v4"
 	"github.com/vbauerster/mpb/v4/decor"
 )
 
 const (
-	AbstractIndexDE   = "https://dumps.wikimedia.org/dewiki/latest/dewiki-latest-pages-articles.xml.bz2"
-	SpecialCharacters = "!$@_"
-)
-
-var (
-	PersonDataTemplateRegExpDE = regexp.MustCompile(`(?i:\{\{personendaten([^\}]+)\}\})`)
-	TemplateFieldsRegExp       = regexp.MustCompile(`(?i:\s*([a-z]+)\s*=[\t\n\f\r '"ʿ]*(.+)[\t\n\f\r '"ʿ]*)`)
-	NameSeperatorRegExp        = regexp.MustCompile(`\s*,\s*`)
-	FirstnameSeperatorRegExp   = regexp.MustCompile(`[\t\n\f\r \-\.'"ʿ]`)
+	WikidataEndpoint  = "https://query.wikidata.org/sparql"
+	ProjectedNames    = 50000
+	AverageNameLength = 6
 )
 
 // ...
@@ -57,47 +61,313 @@ func (m *ProgressReader) Read(p []byte) (int, error) {
 	return n, err
 }
 
-// Wikipedia XML
-type WikipediaRevision struct {
-	ID       int    `xml:"id"`
-	ParentID int    `xml:"parentid"`
-	Text     string `xml:"text"`
+// SeededAverage is a moving average that reports the arithmetic mean of the first samples and continues
+// as an exponentially weighted moving average seeded with that mean afterwards.
+type SeededAverage struct {
+	average ewma.MovingAverage // Moving average after seeding
+	seeds   int                // Number of seed samples
+	count   int                // Number of seed samples so far
+	sum     float64            // Sum of seed samples
 }
 
-type WikipediaPage struct {
-	Title     string               `xml:"title"`    // Title in text form. (Using spaces, not underscores; with namespace)
-	Namespace string               `xml:"ns"`       // Namespace in canonical form
-	ID        int                  `xml:"id"`       // Optional page ID number
-	Redirect  string               `xml:"redirect"` // Flag if the current revision is a redirect
-	Revision  []*WikipediaRevision `xml:"revision"` // Set of revisions
+func NewSeededAverage(age float64, seeds int) *SeededAverage {
+	return &SeededAverage{
+		average: ewma.NewMovingAverage(age),
+		seeds:   seeds,
+	}
 }
 
-// Main entry point
-func main() {
-	// Print banner
-	logoClr := color.New(color.FgHiCyan)
+func (a *SeededAverage) Add(value float64) {
+	if a.count >= a.seeds {
+		a.average.Add(value)
+		return
+	}
 
-	logoClr.Fprintln(os.Stderr, "                                                              __ __ __       __    ")
-	logoClr.Fprintln(os.Stderr, ".-.--..---.-.--.-.--.-----.-----._____.--._.--.-----.--.--.--|  |  |__|-----|  |_  ")
-	logoClr.Fprintln(os.Stderr, "|  .  |  -  |  . .  |  -__|__ --|_____|  | |  |  -  |  .__|  -  |  |  |__ --|   _| ")
-	logoClr.Fprintln(os.Stderr, "|__|__|___._|__|-|__|_____|_____|     |___.___|_____|__|  |_____|__|__|_____|_____|")
-	logoClr.Fprintln(os.Stderr, "                                                                                   ")
+	a.count++
+	a.sum += value
+
+	if a.count == a.seeds {
+		a.average.Set(a.sum / float64(a.count))
+	}
+}
+
+func (a *SeededAverage) Value() float64 {
+	if a.count >= a.seeds {
+		return a.average.Value()
+	}
+
+	if a.count == 0 {
+		return 0
+	}
+
+	return a.sum / float64(a.count)
+}
+
+func (a *SeededAverage) Set(value float64) {
+	a.count = a.seeds
+	a.average.Set(value)
+}
+
+// Examples shown in the usage of the root command
+const rootExample = `  # Generate from a previously downloaded dump
+  names-wordlist --dump-file dewiki-latest-pages-articles.xml.bz2 output.lst
 
+  # Generate from the French Wikipedia (defined in the example languages file)
+  names-wordlist --languages-file etc/languages.yaml --language fr output.lst
+
+  # Sort and deduplicate the wordlists of two languages
+  names-wordlist --language de output-de.lst && names-wordlist --language pl output-pl.lst
+  names-wordlist merge --sort -o merged.lst output-de.lst output-pl.lst
+
+  # Append up to two digits only, without special characters (much smaller output)
+  names-wordlist --digits 2 --special-chars "" output.lst
+
+  # Write names only as extracted, without any variants
+  names-wordlist --names-only --count 10 names.lst`
+
+// Main entry point
+func main() {
 	// Cobra command
 	cmd := &cobra.Command{
 		Use:     "names-wordlist",
 		Long:    "Create wordlists based on Wikipedia person data.",
-		Args:    cobra.ExactArgs(1),
+		Example: rootExample,
+		Args:    cobra.MaximumNArgs(1),
 		Version: "1.0.0",
 		Run:     namesWordlist,
 	}
 
 	cmd.Flags().BoolP("verbose", "v", false, "write more")
+	cmd.Flags().BoolP("quiet", "q", false, "write neither banner nor progress bar, and only warnings and errors")
+	cmd.Flags().String("log-level", "", "write log messages of this level or above ('trace' also logs each extracted name)")
+	cmd.Flags().String("config", "", "read options from this config file (YAML, TOML, or JSON) instead of config.* in the search path")
+	cmd.Flags().StringP("profile", "p", "", "apply options of this profile from the config file")
+	cmd.Flags().Bool("version-check", false, "check for a newer release on GitHub before starting")
 
+	cmd.Flags().Duration("progress-refresh", 120*time.Millisecond, "refresh the progress bar in this interval")
+	cmd.Flags().String("progress-eta", "ewma", "estimate remaining time using 'ewma' or 'linear'")
+	cmd.Flags().Float64("progress-window", 64, "use a window of N reads for the 'ewma' estimate")
+	cmd.Flags().Bool("verbose-progress", false, "log the number of pages and names processed every "+strconv.Itoa(wordlist.ProgressPages)+" pages")
+	cmd.Flags().Int("progress-seed", 10, "seed the 'ewma' estimate with the average of the first N reads")
+
+	cmd.Flags().StringSliceP("language", "l", []string{"de"}, "use Wikipedia dumps of these languages ("+strings.Join(wordlist.LanguageCodes(), ", ")+")")
+	cmd.Flags().String("languages-file", "", "load additional language definitions from this YAML file")
+	cmd.Flags().String("name-order", "", "read template values as 'lastfirst' or 'firstlast' (default depends on language)")
+	cmd.Flags().String("name-separator", "", "split template values at this regular expression (default depends on name order)")
+	cmd.Flags().String("firstname-tokens", "first", "use the 'first' of multiple first names, or keep all of them 'joined'")
+	cmd.Flags().Int("words-per-name", 1, "count each of the first N of multiple first names on its own (i.e. \"Mary\" and \"Anne\" for 2)")
+	cmd.Flags().Bool("emit-all-parts", false, "count each of multiple first names on its own (may increase the output significantly)")
+	cmd.Flags().StringSlice("token-separators", []string{"", "_", "-"}, "join multiple first names with these separators")
+	cmd.Flags().Bool("use-title-fallback", false, "read the name from the title of pages in person categories without person data")
+	cmd.Flags().Bool("split-camel-case", false, "split template values without separator at inner capitals (i.e. \"JohnDoe\")")
 	cmd.Flags().StringP("dump-url", "u", "", "overwrite default URL for given language")
-	cmd.Flags().IntP("count", "c", 1, "ignore names with less than N occurences")
-	cmd.Flags().IntP("digits", "d", 4, "append up to N digits after the name")
-	cmd.Flags().StringP("special-chars", "s", SpecialCharacters, "append special characters from this set")
+	cmd.Flags().StringP("dump-file", "f", "", "read dump from local file instead of downloading it")
+	cmd.Flags().Bool("watch", false, "regenerate the wordlist whenever the dump file changes")
+	cmd.Flags().Bool("use-multistream", false, "only read streams of the multistream dump with pages titled like persons (using its index)")
+	cmd.Flags().String("multistream-index", "", "read the index of the multistream dump from this file or URL (default next to the dump)")
+	cmd.Flags().String("multistream-titles", "", "select pages with titles matching this regular expression (default \"Firstname Lastname\")")
+	cmd.Flags().String("bearer-token", "", "send this token in the Authorization header when downloading (i.e. from a private mirror)")
+	cmd.Flags().String("ca-cert", "", "trust the CA certificates in this PEM file when downloading")
+	cmd.Flags().Bool("insecure", false, "skip TLS certificate verification when downloading")
+	cmd.Flags().String("download-rate", "", "download at no more than this rate, i.e. 5MiB/s (empty for no limit)")
+	cmd.Flags().String("csv-input", "", "read names from a CSV/TSV file instead of a Wikipedia dump")
+	cmd.Flags().Int("name-column", 1, "read names from the N-th column of the CSV/TSV file")
+	cmd.Flags().Bool("csv-header", false, "skip the first line of the CSV/TSV file")
+	cmd.Flags().String("sqlite-input", "", "read page texts from a SQLite database instead of a Wikipedia dump")
+	cmd.Flags().String("sqlite-table", "pages", "read (page_id, text) columns from this table of the SQLite database")
+	cmd.Flags().Bool("wikidata", false, "read given names and their frequency from Wikidata instead of a Wikipedia dump")
+	cmd.Flags().String("wikidata-url", WikidataEndpoint, "query this Wikidata SPARQL endpoint")
+	cmd.Flags().Int("wikidata-page-size", 10000, "fetch N names per Wikidata query")
+	cmd.Flags().String("wikidata-dump", "", "read given names of humans from this Wikidata JSON dump instead of a Wikipedia dump")
+	cmd.Flags().String("compression", "auto", "decompress dump using 'auto', 'none', 'bzip2', 'gzip', 'xz', or 'zstd'")
+	cmd.Flags().Bool("parallel-decompress", false, "decompress streams of multi-stream bzip2 dumps in parallel")
+	cmd.Flags().Int("decompress-workers", runtime.NumCPU(), "decompress with N workers in parallel")
+	cmd.Flags().Bool("strict", false, "abort on the first page that cannot be decoded instead of skipping it")
+	cmd.Flags().Bool("exclude-redirects", true, "skip redirect pages")
+	cmd.Flags().Bool("redirect-follow", false, "count names once more for each redirect to their page (reads the dump twice)")
+	cmd.Flags().Int("namespace", 0, "skip pages outside this namespace (0 for articles)")
+	cmd.Flags().Int("sample", 0, "stop after processing N pages (0 for all)")
+	cmd.Flags().Bool("random-sample", false, "sample N pages at random from the whole dump instead of the first ones")
+	cmd.Flags().Int64("seed", 0, "seed the random sample with this number (0 for a random seed)")
+	cmd.Flags().String("stopwords-file", "", "skip tokens listed in this file in addition to built-in stopwords")
+	cmd.Flags().StringSlice("name-transform", nil, "replace strings in extracted names, given as from=to (can be repeated)")
+	cmd.Flags().String("charset", "", "drop names with other characters than 'ascii' letters, 'alnum' (letters and digits), or the given ones")
+	cmd.Flags().IntP("count", "c", 1, "ignore names with less than N occurences (raise to shrink the output)")
+	cmd.Flags().Int("require-min-names", 1, "exit with code 2 if less than N names are written")
+	cmd.Flags().Int("count-max", 0, "ignore names with more than N occurences (0 for no limit)")
+	cmd.Flags().Float64("count-percentile", 0, "ignore names occuring less often than the names at this percentile (i.e. 90 for the top 10 %)")
+	cmd.Flags().Int("top-n", 0, "keep only the N most frequent names (0 for all)")
+	cmd.Flags().IntP("digits", "d", 4, "append up to N digits after the name (each digit multiplies the output by about 10)")
+	cmd.Flags().Bool("birth-years", false, "append the birth years of persons with a name instead of digits, where known")
+	cmd.Flags().StringP("special-chars", "s", wordlist.SpecialCharacters, "append special characters from this set (each character adds one entry per digit suffix)")
+	cmd.Flags().String("pattern", wordlist.DefaultPattern, "put entries together in this order of "+wordlist.PatternName+", "+wordlist.PatternDigits+", "+wordlist.PatternSpecial+", and literal text")
+	cmd.Flags().Bool("keyboard-walks", false, "also append keyboard walks (i.e. \"1qaz\" or \"qwerty\")")
+	cmd.Flags().String("keyboard-walk-file", "", "load keyboard walks from this file instead of the built-in ones")
+	cmd.Flags().Bool("output-append", false, "append to output file instead of truncating it")
+	cmd.Flags().String("output-compression", "auto", "compress output using 'auto' (by extension), 'none', 'gzip', or 'zstd'")
+	cmd.Flags().Int("output-compression-level", 0, "compress output with this level (0 for the default level)")
+	cmd.Flags().Int("channel-buffer", 100, "buffer up to N names for the output routine")
+	cmd.Flags().Bool("names-only", false, "write each name once as extracted (or in the case variants selected), without digits or special characters")
+	cmd.Flags().StringSlice("case", wordlist.DefaultCases, "write names in these case variants ("+strings.Join(wordlist.Cases, ", ")+")")
+	cmd.Flags().String("title-mode", "word", "capitalize each 'word' of a name in title case, or only the first letter of a 'single' name")
+	cmd.Flags().Bool("capitalize-first-only", false, "write the title case variant with only the very first letter capitalized")
+	cmd.Flags().MarkDeprecated("capitalize-first-only", "use --title-mode single instead")
+	cmd.Flags().Bool("preserve-case", false, "also write names in their original casing (i.e. \"McDonald\")")
+	cmd.Flags().String("name-prefix", "", "prepend this string to each name (i.e. \"svc_\")")
+	cmd.Flags().String("name-suffix", "", "append this string to each name, before digits and special characters")
+	cmd.Flags().Bool("phonetic", false, "also write phonetically similar variants of first names (i.e. \"Carl\" for \"Karl\")")
+	cmd.Flags().String("phonetic-rules", "", "load phonetic rules from this YAML file instead of the built-in ones")
+	cmd.Flags().Int("max-variants-per-name", 0, "write at most N entries per name, most likely ones first (0 for no limit)")
+	cmd.Flags().Int("flush-interval", 10000, "flush output after every N entries (0 to flush at the end only)")
+	cmd.Flags().String("per-name-dir", "", "write the entries of each name to its own file in this directory instead of an output file")
+	cmd.Flags().String("per-name-compress", "none", "compress the files of each name using 'none', 'gzip', or 'zstd'")
+	cmd.Flags().Int("output-buffer-size", 4<<20, "buffer up to N bytes of output before writing it")
+	cmd.Flags().Duration("output-buffer-flush-interval", 0, "also flush output in this interval, i.e. 500ms when piping (0 to flush only when needed)")
+	cmd.Flags().Bool("progress-output", false, "log the number of entries written every "+strconv.Itoa(wordlist.ProgressLines)+" entries")
+	cmd.Flags().Float64("size-limit", 100, "ask for confirmation if the output is projected to exceed N GiB (0 for no limit)")
+	cmd.Flags().BoolP("yes", "y", false, "do not ask for confirmation")
+	cmd.Flags().String("line-ending", "lf", "terminate entries with 'lf' or 'crlf'")
+	cmd.Flags().BoolP("reverse", "r", false, "also add names in reversed order")
+	cmd.Flags().Bool("combine", false, "also add combinations of first and last names")
+	cmd.Flags().StringSlice("combine-separators", []string{"", ".", "_", "-"}, "join first and last names with these separators")
+
+	cmd.Flags().String("checkpoint", "", "periodically save progress to this file and resume from it when restarted")
+	cmd.Flags().Duration("checkpoint-interval", 5*time.Minute, "save progress to the checkpoint file in this interval")
+	cmd.Flags().String("mask-out", "", "also write hashcat masks of names, ranked by the number of names fitting them, to this file")
+	cmd.Flags().String("template-debug", "", "write each matched person data template with the title of its page to this file")
+	cmd.Flags().String("field-debug", "", "write each field of matched person data templates with the title of its page to this file")
+	cmd.Flags().String("freq-out", "", "also write names with their number of occurences to this file")
+	cmd.Flags().String("manifest", "", "write a JSON summary of how the wordlist was generated to this file")
+	cmd.Flags().String("freq-format", "plain", "write frequencies as 'plain' (count name) or 'csv' (name,count)")
+
+	cmd.Flags().Bool("benchmark", false, "measure parsing throughput without writing any output, printing it to stdout")
+	cmd.Flags().Duration("benchmark-duration", 0, "stop benchmark after this duration (0 for the whole dump)")
+
+	cmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		// Keep "--append" as an alias
+		if name == "append" {
+			name = "output-append"
+		}
+
+		// Accept "--force" as an alias
+		if name == "force" {
+			name = "yes"
+		}
+
+		return pflag.NormalizedName(name)
+	})
+
+	// Sub commands
+	cmd.AddCommand(&cobra.Command{
+		Use:   "config-generate [path]",
+		Short: "Write a sample config file with all options and their defaults",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   configGenerate,
+	})
+
+	mergeCmd := &cobra.Command{
+		Use:   "merge [flags] wordlist...",
+		Short: "Merge and deduplicate multiple wordlist files",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   merge,
+	}
+
+	mergeCmd.Flags().StringP("output", "o", "-", "write merged wordlist to this file")
+	mergeCmd.Flags().Bool("sort", false, "sort merged wordlist")
+
+	cmd.AddCommand(mergeCmd)
+
+	filterCmd := &cobra.Command{
+		Use:   "filter [flags] wordlist",
+		Short: "Filter an existing wordlist by length and pattern",
+		Args:  cobra.ExactArgs(1),
+		Run:   filter,
+	}
+
+	filterCmd.Flags().StringP("output", "o", "-", "write filtered wordlist to this file")
+	filterCmd.Flags().Int("min-length", 0, "drop entries shorter than N characters")
+	filterCmd.Flags().Int("max-length", 0, "drop entries longer than N characters (0 for no limit)")
+	filterCmd.Flags().StringArray("include-pattern", nil, "keep only entries matching this regular expression (can be repeated)")
+	filterCmd.Flags().StringArray("exclude-pattern", nil, "drop entries matching this regular expression (can be repeated)")
+
+	cmd.AddCommand(filterCmd)
+
+	statsCmd := &cobra.Command{
+		Use:   "stats [flags] wordlist",
+		Short: "Print statistics about an existing wordlist",
+		Args:  cobra.ExactArgs(1),
+		Run:   stats,
+	}
+
+	statsCmd.Flags().Int("top", 10, "show the N most common suffixes")
+
+	cmd.AddCommand(statsCmd)
+
+	lookupCmd := &cobra.Command{
+		Use:   "lookup [flags] name...",
+		Short: "Show the entries that would be written for names in a frequency histogram",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   lookup,
+	}
+
+	lookupCmd.Flags().String("histogram", "", "read name frequencies from this file (as written with --freq-out)")
+	lookupCmd.Flags().IntP("count", "c", 1, "ignore names with less than N occurences")
+	lookupCmd.Flags().IntP("digits", "d", 4, "append up to N digits after the name")
+	lookupCmd.Flags().StringP("special-chars", "s", wordlist.SpecialCharacters, "append special characters from this set")
+	lookupCmd.Flags().StringSlice("case", wordlist.DefaultCases, "write names in these case variants ("+strings.Join(wordlist.Cases, ", ")+")")
+	lookupCmd.MarkFlagRequired("histogram")
+
+	cmd.AddCommand(lookupCmd)
+
+	serveCmd := &cobra.Command{
+		Use:   "serve [flags]",
+		Short: "Serve wordlists generated on request from pre-loaded frequency histograms",
+		Args:  cobra.NoArgs,
+		Run:   serve,
+	}
+
+	serveCmd.Flags().String("listen", ":8080", "listen on this address")
+	serveCmd.Flags().StringToString("histogram", nil, "read name frequencies for a language from this file (i.e. de=names-de.csv, can be repeated)")
+	serveCmd.Flags().StringSlice("case", wordlist.DefaultCases, "write names in these case variants ("+strings.Join(wordlist.Cases, ", ")+")")
+	serveCmd.Flags().Int("max-digits", 4, "reject requests for more than N digits")
+	serveCmd.Flags().Int("max-special-chars", 32, "reject requests for more than N special characters")
+	serveCmd.MarkFlagRequired("histogram")
+
+	cmd.AddCommand(serveCmd)
+
+	dumpsCmd := &cobra.Command{
+		Use:   "dumps [flags]",
+		Short: "List the dumps of all known languages with their size and time of last modification",
+		Args:  cobra.NoArgs,
+		Run:   dumps,
+	}
+
+	dumpsCmd.Flags().String("languages-file", "", "also list languages defined in this YAML file")
+	dumpsCmd.Flags().String("ca-cert", "", "trust the CA certificates in this PEM file")
+	dumpsCmd.Flags().Bool("insecure", false, "skip TLS certificate verification")
+	dumpsCmd.Flags().Duration("timeout", 10*time.Second, "give up on a dump after this time")
+
+	cmd.AddCommand(dumpsCmd)
+
+	diffCmd := &cobra.Command{
+		Use:   "diff [flags] wordlist-a wordlist-b",
+		Short: "Compare two wordlists, writing entries only in either of them or in both",
+		Args:  cobra.ExactArgs(2),
+		Run:   diff,
+	}
+
+	diffCmd.Flags().String("only-a", "", "write entries only in the first wordlist to this file")
+	diffCmd.Flags().String("only-b", "", "write entries only in the second wordlist to this file")
+	diffCmd.Flags().String("both", "", "write entries in both wordlists to this file")
+
+	cmd.AddCommand(diffCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "check wordlist passwords",
+		Short: "Check how many known password hashes (MD5 or SHA-1) are found in a wordlist",
+		Args:  cobra.ExactArgs(2),
+		Run:   check,
+	})
 
 	// Viper config
 	viper.SetEnvPrefix("NAMES_WORDLIST")
@@ -113,181 +383,1733 @@ func main() {
 
 	viper.ReadInConfig()
 
+	// Print banner once flags are parsed (unless quiet). The root command prints it itself, after applying the
+	// config file and profile (which may set quiet as well).
+	cmd.PersistentPreRun = func(c *cobra.Command, args []string) {
+		if c != cmd && !viper.GetBool("quiet") {
+			printBanner()
+		}
+	}
+
 	// Run command
 	cmd.Execute()
 }
 
+// printBanner writes the banner to stderr.
+func printBanner() {
+	logoClr := color.New(color.FgHiCyan)
+
+	logoClr.Fprintln(os.Stderr, "                                                              __ __ __       __    ")
+	logoClr.Fprintln(os.Stderr, ".-.--..---.-.--.-.--.-----.-----._____.--._.--.-----.--.--.--|  |  |__|-----|  |_  ")
+	logoClr.Fprintln(os.Stderr, "|  .  |  -  |  . .  |  -__|__ --|_____|  | |  |  -  |  .__|  -  |  |  |__ --|   _| ")
+	logoClr.Fprintln(os.Stderr, "|__|__|___._|__|-|__|_____|_____|     |___.___|_____|__|  |_____|__|__|_____|_____|")
+	logoClr.Fprintln(os.Stderr, "                                                                                   ")
+}
+
 // aykroyd is called if the CLI interfaces has been satisfied.
 func namesWordlist(cmd *cobra.Command, args []string) {
+	// Read config file given explicitly instead of the one found in the search path (in any format supported by
+	// viper, i.e. YAML, TOML, or JSON)
+	if path := viper.GetString("config"); path != "" {
+		viper.SetConfigFile(path)
+
+		if err := viper.ReadInConfig(); err != nil {
+			logrus.Errorf("Unable to read config file: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Apply profile
+	if profile := viper.GetString("profile"); profile != "" {
+		if err := ApplyProfile(profile, cmd.Flags()); err != nil {
+			logrus.Errorf("Unable to apply profile: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Print banner (unless quiet)
+	if !viper.GetBool("quiet") {
+		printBanner()
+	}
+
 	// Set logging level
-	if viper.GetBool("verbose") {
+	if level := viper.GetString("log-level"); level != "" {
+		l, err := logrus.ParseLevel(level)
+		if err != nil {
+			logrus.Errorf("Unknown log level: %s", level)
+			os.Exit(1)
+		}
+
+		logrus.SetLevel(l)
+	} else if viper.GetBool("verbose") {
 		logrus.SetLevel(logrus.DebugLevel)
+	} else if viper.GetBool("quiet") {
+		logrus.SetLevel(logrus.WarnLevel)
 	} else {
 		logrus.SetLevel(logrus.InfoLevel)
 	}
 
-	// Download Wikipedia Dump
-	dumpUrl := viper.GetString("dump-url")
-	if dumpUrl == "" {
-		dumpUrl = AbstractIndexDE
+	// Check for a newer release
+	if viper.GetBool("version-check") {
+		checkVersion(cmd.Root().Version)
 	}
 
-	resp, err := http.Get(dumpUrl)
-	if err != nil {
-		logrus.Errorf("Unable to fetch abstract index: %w", err)
+	// Check output file
+	benchmark := viper.GetBool("benchmark")
+
+	perNameDir := viper.GetString("per-name-dir")
+
+	if !benchmark && len(args) == 0 && perNameDir == "" {
+		logrus.Errorf("Missing output file")
 		os.Exit(1)
 	}
 
-	defer resp.Body.Close()
+	if perNameDir != "" && len(args) > 0 {
+		logrus.Errorf("Output file cannot be given with --per-name-dir")
+		os.Exit(1)
+	}
 
-	// Show progress
-	p := mpb.New()
+	if c := viper.GetString("per-name-compress"); c != "none" && c != "gzip" && c != "zstd" {
+		logrus.Errorf("Unknown per name compression: %s", c)
+		os.Exit(1)
+	}
 
-	bar := p.AddBar(resp.ContentLength,
-		mpb.PrependDecorators(decor.CountersKibiByte("% .2f / % .2f")),
-		mpb.AppendDecorators(
-			decor.Percentage(),
-			decor.Name(" | ETA: "),
-			decor.EwmaETA(decor.ET_STYLE_HHMMSS, 64),
-		),
-	)
+	// Regenerate whenever the dump file changes
+	if viper.GetBool("watch") {
+		path := viper.GetString("dump-file")
+		if path == "" {
+			logrus.Errorf("Only a dump file given with --dump-file can be watched")
+			os.Exit(1)
+		}
 
-	pr := NewProgressReader(bar, resp.Body)
+		watchDump(path)
+	}
 
-	// Decompress Bzip2
-	decr := bzip2.NewReader(pr)
+	// Check frequency format
+	if f := viper.GetString("freq-format"); f != "plain" && f != "csv" {
+		logrus.Errorf("Unknown frequency format: %s", f)
+		os.Exit(1)
+	}
 
-	// Open output file
-	out, err := os.Create(args[0])
-	if err != nil {
-		fmt.Errorf("Unable to create output file: %w", err)
+	// Load additional languages
+	if path := viper.GetString("languages-file"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			logrus.Errorf("Unable to open languages file: %v", err)
+			os.Exit(1)
+		}
+
+		err = wordlist.LoadLanguages(f)
+		f.Close()
+
+		if err != nil {
+			logrus.Errorf("Unable to load languages file: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Select languages
+	codes := viper.GetStringSlice("language")
+
+	for _, code := range codes {
+		if _, ok := wordlist.Languages[code]; !ok {
+			logrus.Errorf("Unknown language: %s", code)
+			os.Exit(1)
+		}
+	}
+
+	if len(codes) > 1 && (viper.GetString("dump-url") != "" || viper.GetString("dump-file") != "") {
+		logrus.Errorf("Dump URL or file can only be given for a single language")
 		os.Exit(1)
 	}
 
-	defer out.Close()
+	if len(codes) > 1 && viper.GetString("sqlite-input") != "" {
+		logrus.Errorf("SQLite database can only be given for a single language")
+		os.Exit(1)
+	}
 
-	// Spin off output routne
-	ch := make(chan string, 100)
-	wg := &sync.WaitGroup{}
+	if !SQLiteSupported && viper.GetString("sqlite-input") != "" {
+		logrus.Errorf("SQLite databases can only be read by builds with cgo enabled (CGO_ENABLED=1)")
+		os.Exit(1)
+	}
 
-	wg.Add(1)
-	go OutputRoutine(
-		out,
-		viper.GetInt("digits"),
-		viper.GetString("special-chars"),
-		ch,
-		wg,
-	)
+	if len(codes) > 1 && viper.GetBool("wikidata") {
+		logrus.Errorf("Wikidata can only be queried for a single language")
+		os.Exit(1)
+	}
 
-	// Streamed XML parsing
-	firstnameHist := make(map[string]int)
-	cnt := viper.GetInt("count")
+	if len(codes) > 1 && viper.GetString("wikidata-dump") != "" {
+		logrus.Errorf("Wikidata dump can only be read for a single language")
+		os.Exit(1)
+	}
+
+	// Overwrite name order and separator of all selected languages
+	order := viper.GetString("name-order")
+	if order != "" && !wordlist.ValidNameOrder(order) {
+		logrus.Errorf("Unknown name order: %s", order)
+		os.Exit(1)
+	}
 
-	decoder := xml.NewDecoder(decr)
-	for {
-		token, err := decoder.Token()
-		if token == nil || err == io.EOF {
-			break
-		} else if err != nil {
-			logrus.Errorf("Error decoding XML token: %w", err)
+	var separator *regexp.Regexp
+	if expr := viper.GetString("name-separator"); expr != "" {
+		var err error
+		if separator, err = regexp.Compile(expr); err != nil {
+			logrus.Errorf("Invalid name separator: %v", err)
 			os.Exit(1)
 		}
+	}
 
-		switch t := token.(type) {
-		case xml.StartElement:
-			if t.Name.Local == "page" {
-				// Decode <page> element
-				var p WikipediaPage
+	tokens := viper.GetString("firstname-tokens")
+	if tokens != "first" && tokens != "joined" {
+		logrus.Errorf("Unknown first name tokens mode: %s", tokens)
+		os.Exit(1)
+	}
 
-				if err = decoder.DecodeElement(&p, &t); err != nil {
-					continue
-				}
+	if viper.GetBool("birth-years") {
+		for _, code := range codes {
+			if wordlist.Languages[code].BirthDate == nil {
+				logrus.Warnf("Birth dates are unknown for language %s, appending digits to all names", code)
+			}
+		}
+	}
 
-				// Skip if no or empty revision
-				if len(p.Revision) == 0 || p.Revision[0] == nil {
-					continue
-				}
+	if viper.GetInt("words-per-name") < 1 {
+		logrus.Errorf("Invalid number of words per name: %d", viper.GetInt("words-per-name"))
+		os.Exit(1)
+	}
 
-				// Iterate through all {{Persondata}} templates
-				templates := PersonDataTemplateRegExpDE.FindAllStringSubmatch(p.Revision[0].Text, -1)
-				for _, tmpl := range templates {
-					// Split into fields
-					for _, sub := range strings.Split(tmpl[1], "|") {
-						// Parse key/value of field
-						kv := TemplateFieldsRegExp.FindStringSubmatch(sub)
-						if kv == nil {
-							continue
-						}
+	if wordsPerName() != 1 && tokens == "joined" {
+		logrus.Errorf("Multiple words per name can only be counted with 'first' first name tokens")
+		os.Exit(1)
+	}
 
-						switch strings.ToLower(kv[1]) {
-						case "name":
-							// Split last- and firstname
-							name := NameSeperatorRegExp.Split(kv[2], -1)
-							if len(name) < 2 {
-								continue
-							}
-
-							// Split multiple firstnames
-							firstname := FirstnameSeperatorRegExp.Split(name[1], -1)
-							if len(firstname) < 1 {
-								continue
-							}
-
-							// Increment usage
-							firstnameHist[firstname[0]] += 1
-
-							// Output
-							if firstnameHist[firstname[0]] == cnt {
-								ch <- firstname[0]
-							}
-						}
-					}
-				}
+	for _, code := range codes {
+		if order != "" {
+			wordlist.Languages[code].NameOrder = order
+		}
+
+		if separator != nil {
+			wordlist.Languages[code].NameSeparator = separator
+		}
+	}
+
+	// Select line ending
+	var lineEnding string
+
+	switch viper.GetString("line-ending") {
+	case "lf":
+		lineEnding = "\n"
+	case "crlf":
+		lineEnding = "\r\n"
+	default:
+		logrus.Errorf("Unknown line ending: %s", viper.GetString("line-ending"))
+		os.Exit(1)
+	}
+
+	// Load phonetic rules
+	var phoneticRules []wordlist.PhoneticRule
+
+	if viper.GetBool("phonetic") {
+		phoneticRules = wordlist.DefaultPhoneticRules
+
+		if path := viper.GetString("phonetic-rules"); path != "" {
+			f, err := os.Open(path)
+			if err != nil {
+				logrus.Errorf("Unable to open phonetic rules file: %v", err)
+				os.Exit(1)
+			}
+
+			phoneticRules, err = wordlist.LoadPhoneticRules(f)
+			f.Close()
+
+			if err != nil {
+				logrus.Errorf("Unable to load phonetic rules file: %v", err)
+				os.Exit(1)
 			}
-		default:
 		}
 	}
 
-	// Clean up output go routine
-	close(ch)
-	wg.Wait()
-}
+	// Load keyboard walks
+	var keyboardWalks []string
 
-// ...
-func OutputRoutine(w io.StringWriter, digits int, specialChars string, ch chan string, wg *sync.WaitGroup) {
-	wg.Done()
+	if viper.GetBool("keyboard-walks") {
+		keyboardWalks = wordlist.DefaultKeyboardWalks
+
+		if path := viper.GetString("keyboard-walk-file"); path != "" {
+			f, err := os.Open(path)
+			if err != nil {
+				logrus.Errorf("Unable to open keyboard walk file: %v", err)
+				os.Exit(1)
+			}
+
+			keyboardWalks, err = wordlist.LoadKeyboardWalks(f)
+			f.Close()
+
+			if err != nil {
+				logrus.Errorf("Unable to load keyboard walk file: %v", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	// Check case variants (capitalizing only the first letter instead of each word if requested)
+	titleMode := viper.GetString("title-mode")
+	if !wordlist.ValidTitleMode(titleMode) {
+		logrus.Errorf("Unknown title mode: %s", titleMode)
+		os.Exit(1)
+	}
+
+	if viper.GetBool("capitalize-first-only") {
+		titleMode = wordlist.TitleSingle
+	}
 
-	// Create number combinations
-	digitCombs := []string{""}
+	var caseVariants []string
+	seenCases := make(map[string]bool)
 
-	maxNumber := 1
-	for d := 0; d < digits; d++ {
-		maxNumber *= 10
-		format := fmt.Sprintf("%%0%dd", d+1)
+	for _, c := range viper.GetStringSlice("case") {
+		if !wordlist.ValidCase(c) {
+			logrus.Errorf("Unknown case: %s", c)
+			os.Exit(1)
+		}
+
+		if c == "title" && titleMode == wordlist.TitleSingle {
+			c = "capitalize"
+		}
+
+		if !seenCases[c] {
+			seenCases[c] = true
+			caseVariants = append(caseVariants, c)
+		}
+	}
+
+	// Select ETA estimator
+	switch viper.GetString("progress-eta") {
+	case "ewma", "linear":
+	default:
+		logrus.Errorf("Unknown ETA estimator: %s", viper.GetString("progress-eta"))
+		os.Exit(1)
+	}
+
+	// Load stopwords
+	stopwords := wordlist.NewStopwords(wordlist.DefaultStopwords...)
+
+	if path := viper.GetString("stopwords-file"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			logrus.Errorf("Unable to open stopwords file: %v", err)
+			os.Exit(1)
+		}
+
+		err = stopwords.Load(f)
+		f.Close()
+
+		if err != nil {
+			logrus.Errorf("Unable to read stopwords file: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Spin off output routne
+	ch := make(chan wordlist.Name, viper.GetInt("channel-buffer"))
+	wg := &sync.WaitGroup{}
+
+	// Send names to the output routine, keeping track of the time spent waiting for it
+	var stalls, stallTime int64
+
+	var sent int64
 
-		for i := 0; i < maxNumber; i++ {
-			digitCombs = append(digitCombs, fmt.Sprintf(format, i))
+	send := func(n wordlist.Name) {
+		atomic.AddInt64(&sent, 1)
+
+		select {
+		case ch <- n:
+		default:
+			t := time.Now()
+			ch <- n
+
+			atomic.AddInt64(&stalls, 1)
+			atomic.AddInt64(&stallTime, int64(time.Since(t)))
 		}
 	}
 
-	// Create special character combinations
-	charCombs := []string{""}
+	stats := &wordlist.OutputStats{}
+	var outCloser io.Closer
+	var outFile *os.File
+
+	interrupt := make(chan struct{})
+
+	// Close output after an interruption or error, keeping a partial output file as *.partial (only once, as
+	// both may happen at the same time)
+	var closePartialOnce sync.Once
+
+	closePartialOutput := func() {
+		closePartialOnce.Do(func() {
+			if outCloser != nil {
+				outCloser.Close()
+			}
+
+			if outFile == nil {
+				return
+			}
+
+			fi, err := outFile.Stat()
+			outFile.Close()
 
-	for _, c := range specialChars {
-		charCombs = append(charCombs, string(c))
+			// Only rename regular files (not named pipes or devices)
+			if err == nil && fi.Mode().IsRegular() && !viper.GetBool("output-append") {
+				if err := os.Rename(args[0], args[0]+".partial"); err != nil {
+					logrus.Errorf("Unable to rename partial output file: %v", err)
+				} else {
+					logrus.Warnf("Partial output written to %s.partial", args[0])
+				}
+			}
+		})
 	}
 
-	// Generate output
-	for name := range ch {
-		// Lower case
-		lwr := strings.ToLower(name)
-		upr := strings.ToUpper(name)
-		ttl := strings.Title(name)
+	if benchmark {
+		wg.Add(1)
+		go DiscardRoutine(ch, wg)
+	} else {
+		opts := &wordlist.OutputOptions{
+			Digits:            viper.GetInt("digits"),
+			SpecialChars:      viper.GetString("special-chars"),
+			Reverse:           viper.GetBool("reverse"),
+			CombineSeparators: viper.GetStringSlice("combine-separators"),
+			TokenSeparators:   viper.GetStringSlice("token-separators"),
+			LineEnding:        lineEnding,
+			MaxVariants:       viper.GetInt("max-variants-per-name"),
+			NamesOnly:         viper.GetBool("names-only"),
+			Cases:             caseVariants,
+			PreserveCase:      viper.GetBool("preserve-case"),
+			NamePrefix:        viper.GetString("name-prefix"),
+			NameSuffix:        viper.GetString("name-suffix"),
+			PhoneticRules:     phoneticRules,
+			KeyboardWalks:     keyboardWalks,
+			FlushInterval:     viper.GetInt("flush-interval"),
+			FlushPeriod:       viper.GetDuration("output-buffer-flush-interval"),
+			BufferSize:        viper.GetInt("output-buffer-size"),
+			ProgressOutput:    viper.GetBool("progress-output"),
+			Interrupt:         interrupt,
+			OnError: func(err error) {
+				logOutputError(err)
+				closePartialOutput()
+				os.Exit(1)
+			},
+		}
+
+		// Put entries together in the given order (leaving out digits or special characters not in it)
+		if pattern := viper.GetString("pattern"); pattern != wordlist.DefaultPattern {
+			var err error
+			if opts.Pattern, err = wordlist.ParseEntryPattern(pattern); err != nil {
+				logrus.Errorf("Invalid pattern: %v", err)
+				os.Exit(1)
+			}
+
+			if !opts.Pattern.Has(wordlist.PatternDigits) {
+				opts.Digits, opts.KeyboardWalks = 0, nil
+			}
+
+			if !opts.Pattern.Has(wordlist.PatternSpecial) {
+				opts.SpecialChars = ""
+			}
+		}
+
+		// Write names only as extracted, unless case variants are selected
+		if opts.NamesOnly && !viper.IsSet("case") && !opts.PreserveCase {
+			opts.Cases, opts.PreserveCase = nil, true
+		}
+
+		// Ask for confirmation before generating huge outputs
+		lines, bytes := ProjectOutput(opts, ProjectedNames, viper.GetBool("combine"))
+		if limit := viper.GetFloat64("size-limit"); limit > 0 && float64(bytes) > limit*(1<<30) {
+			logrus.Warnf("Output is projected to hold %d entries (%.2f GiB) for %d names", lines, float64(bytes)/(1<<30), ProjectedNames)
 
-		for _, d := range digitCombs {
-			for _, c := range charCombs {
-				w.WriteString(lwr + d + c + "\n" + upr + d + c + "\n" + ttl + d + c + "\n")
+			if !viper.GetBool("yes") && !Confirm("Continue anyway?") {
+				logrus.Errorf("Aborted, use --yes to generate the output anyway")
+				os.Exit(1)
 			}
 		}
+
+		// Warn if the uncompressed output is projected to not fit on its disk (as the projection is rough, it's
+		// written anyway)
+		if perNameDir == "" && args[0] != "-" && !IsNamedPipe(args[0]) &&
+			OutputCompression(viper.GetString("output-compression"), args[0]) == "none" {
+			if free, ok := FreeSpace(filepath.Dir(args[0])); ok && bytes > free {
+				logrus.Warnf("Output is projected to take %.2f GiB, but only %.2f GiB are free on its disk",
+					float64(bytes)/(1<<30), float64(free)/(1<<30))
+			}
+		}
+
+		// Write the entries of each name to its own file
+		if perNameDir != "" {
+			if err := os.MkdirAll(perNameDir, 0777); err != nil {
+				logrus.Errorf("Unable to create per name directory: %v", err)
+				os.Exit(1)
+			}
+
+			opts.NameFile = NewNameFiles(perNameDir, viper.GetString("per-name-compress")).Create
+
+			wg.Add(1)
+			go wordlist.OutputRoutine(ioutil.Discard, opts, ch, stats, wg)
+		} else {
+			// Open output file (or write to stdout for "-")
+			out := os.Stdout
+
+			if args[0] != "-" {
+				if _, err := os.Stat(args[0]); err == nil && !viper.GetBool("output-append") && !IsNamedPipe(args[0]) {
+					logrus.Warnf("Output file %s already exists and will be overwritten (use --output-append to append)", args[0])
+				}
+
+				f, err := OpenOutputFile(args[0], viper.GetBool("output-append"))
+				if err != nil {
+					logrus.Errorf("Unable to create output file: %v", err)
+					os.Exit(1)
+				}
+
+				defer f.Close()
+				out, outFile = f, f
+			}
+
+			// Compress output
+			cw, err := NewCompressWriter(out, viper.GetString("output-compression"), viper.GetInt("output-compression-level"), args[0])
+			if err != nil {
+				logrus.Errorf("Unable to compress output: %v", err)
+				os.Exit(1)
+			}
+
+			outCloser = cw
+
+			wg.Add(1)
+			go wordlist.OutputRoutine(cw, opts, ch, stats, wg)
+		}
+	}
+
+	// On SIGINT or SIGTERM, finish the entries of the current name and close the output, keeping a partial output
+	// file as *.partial. This holds until all output is written (i.e. also while writing deferred names), but not
+	// once it's finished.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+
+	var outputMu sync.Mutex
+	var interrupted, finished bool
+	var onInterrupt func() // Saves a checkpoint while parsing (nil otherwise)
+
+	go func() {
+		sig := <-sigs
+
+		outputMu.Lock()
+		if finished {
+			outputMu.Unlock()
+			return
+		}
+
+		interrupted = true
+		save := onInterrupt
+		outputMu.Unlock()
+
+		logrus.Warnf("Interrupted (%s), closing output", sig)
+
+		if save != nil {
+			save()
+		}
+
+		if !benchmark {
+			close(interrupt)
+			wg.Wait()
+		}
+
+		closePartialOutput()
+		os.Exit(130)
+	}()
+
+	// Count names and output them once they reach the threshold. With an upper bound, names can only be
+	// output once all of them are counted.
+	firstnameHist := &Counter{}
+	combinedHist := &Counter{}
+	cnt := viper.GetInt("count")
+	cntMax := viper.GetInt("count-max")
+
+	if cntMax > 0 && cntMax < cnt {
+		logrus.Errorf("Maximum count %d is less than minimum count %d", cntMax, cnt)
+		os.Exit(1)
+	}
+
+	percentile := viper.GetFloat64("count-percentile")
+	if percentile < 0 || percentile >= 100 {
+		logrus.Errorf("Count percentile must be between 0 and 100: %g", percentile)
+		os.Exit(1)
+	}
+
+	topN := viper.GetInt("top-n")
+	if topN < 0 {
+		logrus.Errorf("Invalid number of top names: %d", topN)
+		os.Exit(1)
+	}
+
+	// Birth years of names (counted by YearKey)
+	birthYears := viper.GetBool("birth-years")
+	yearHist := &Counter{}
+
+	// Names are only sent at the end if the whole histogram (or all birth years) is needed
+	deferred := cntMax > 0 || percentile > 0 || topN > 0 || birthYears
+
+	// Minimum count of names in hist, raised to the count at the percentile
+	minCount := func(hist map[string]int) int {
+		if c := CountPercentile(hist, percentile); percentile > 0 && c > cnt {
+			return c
+		}
+
+		return cnt
+	}
+
+	// Names in hist that occur at least the minimum count and at most max times (0 for no upper bound), most
+	// frequent first and limited to the top N names (ties broken alphabetically)
+	selectNames := func(hist map[string]int, max int) []NameCount {
+		ncs := SortedHistogram(hist, minCount(hist), max)
+		if topN > 0 && len(ncs) > topN {
+			ncs = ncs[:topN]
+		}
+
+		return ncs
+	}
+
+	// Send all names counted so far that pass selectNames
+	sendHistograms := func(max int) {
+		years := YearsByName(yearHist.Snapshot())
+
+		for _, nc := range selectNames(firstnameHist.Snapshot(), max) {
+			send(wordlist.Name{First: nc.Name, Years: years[nc.Name]})
+		}
+
+		for _, nc := range selectNames(combinedHist.Snapshot(), max) {
+			parts := strings.SplitN(nc.Name, " ", 2)
+			send(wordlist.Name{First: parts[0], Last: parts[1], Years: years[nc.Name]})
+		}
+	}
+
+	transforms, err := wordlist.ParseNameTransforms(viper.GetStringSlice("name-transform"))
+	if err != nil {
+		logrus.Errorf("Unable to parse name transforms: %v", err)
+		os.Exit(1)
+	}
+
+	var charset Charset
+	if s := viper.GetString("charset"); s != "" {
+		charset = ParseCharset(s)
+	}
+
+	emit := func(n wordlist.Name) {
+		// Drop names with characters outside the allowed set (before counting them)
+		if charset != nil && (!charset.AllowsTokens(n.First) || !charset.Allows(n.Last)) {
+			return
+		}
+
+		if deferred {
+			key := n.First
+			if n.Last == "" {
+				firstnameHist.Add(key)
+			} else {
+				key += " " + n.Last
+				combinedHist.Add(key)
+			}
+
+			for _, year := range n.Years {
+				yearHist.Add(YearKey(key, year))
+			}
+		} else if n.Last == "" {
+			if firstnameHist.Add(n.First) == cnt {
+				send(n)
+			}
+		} else if combinedHist.Add(n.First+" "+n.Last) == cnt {
+			send(n)
+		}
+	}
+
+	// Show progress (unless quiet)
+	var progressOut io.Writer = os.Stderr
+	if viper.GetBool("quiet") {
+		progressOut = ioutil.Discard
+	}
+
+	p := mpb.New(
+		mpb.WithOutput(progressOut),
+		mpb.WithRefreshRate(viper.GetDuration("progress-refresh")),
+	)
+
+	// Download and parse all dumps in parallel
+	start := time.Now()
+	var deadline time.Time
+
+	if d := viper.GetDuration("benchmark-duration"); benchmark && d > 0 {
+		deadline = start.Add(d)
+	}
+
+	// Create HTTP client
+	var downloadRate float64
+
+	if r := viper.GetString("download-rate"); r != "" {
+		if downloadRate, err = ParseByteRate(r); err != nil {
+			logrus.Errorf("Invalid download rate: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	client, err := NewHTTPClient(viper.GetString("ca-cert"), viper.GetBool("insecure"), downloadRate)
+	if err != nil {
+		logrus.Errorf("Unable to create HTTP client: %v", err)
+		os.Exit(1)
+	}
+
+	if viper.GetBool("insecure") {
+		logrus.Warnf("TLS certificate verification is disabled")
+	}
+
+	// Sample pages at random
+	seed := viper.GetInt64("seed")
+	randomSample := viper.GetBool("random-sample")
+
+	if randomSample {
+		if viper.GetInt("sample") <= 0 {
+			logrus.Errorf("Random sample requires a sample size")
+			os.Exit(1)
+		}
+
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+
+		logrus.Infof("Sampling %d pages at random with seed %d", viper.GetInt("sample"), seed)
+	}
+
+	newReservoir := func() *wordlist.Reservoir {
+		if !randomSample {
+			return nil
+		}
+
+		return wordlist.NewReservoir(viper.GetInt("sample"), seed)
+	}
+
+	// Resume from checkpoint
+	checkpointPath := viper.GetString("checkpoint")
+	var checkpoint *Checkpoint
+
+	checkpointOptions := make(map[string]string, len(CheckpointOptions))
+	for _, name := range CheckpointOptions {
+		checkpointOptions[name] = fmt.Sprint(viper.Get(name))
+	}
+
+	if checkpointPath != "" {
+		if viper.GetString("csv-input") != "" || viper.GetString("sqlite-input") != "" || viper.GetBool("wikidata") || viper.GetString("wikidata-dump") != "" || randomSample || birthYears || viper.GetBool("use-multistream") {
+			logrus.Errorf("Checkpoints are only supported for complete Wikipedia dumps without random sampling or birth years")
+			os.Exit(1)
+		}
+
+		checkpoint, err = LoadCheckpoint(checkpointPath)
+		if err != nil {
+			logrus.Errorf("Unable to load checkpoint: %v", err)
+			os.Exit(1)
+		}
+
+		if checkpoint != nil {
+			if changed := checkpoint.ChangedOptions(checkpointOptions); len(changed) > 0 {
+				logrus.Errorf("The options %s changed since checkpoint %s was saved, remove it to start over", strings.Join(changed, ", "), checkpointPath)
+				os.Exit(1)
+			}
+
+			logrus.Infof("Resuming from checkpoint %s", checkpointPath)
+
+			firstnameHist.Load(checkpoint.Firstnames)
+			combinedHist.Load(checkpoint.Combined)
+
+			// Output names that already reached the threshold before
+			if !deferred {
+				sendHistograms(0)
+			}
+		} else {
+			checkpoint = &Checkpoint{}
+		}
+	}
+
+	// Log parsing progress
+	newProgress := func(code string, dp *wordlist.DumpParser) func() {
+		if !viper.GetBool("verbose-progress") {
+			return nil
+		}
+
+		return func() {
+			logrus.Infof(
+				"[%s] Processed %d pages, found %d names, %d above threshold, output channel %d/%d full",
+				code, dp.Pages, dp.Names, atomic.LoadInt64(&sent), len(ch), cap(ch),
+			)
+		}
+	}
+
+	// Only read streams with pages titled like persons
+	var multistreamTitles *regexp.Regexp
+
+	if viper.GetBool("use-multistream") {
+		if viper.GetBool("redirect-follow") {
+			logrus.Errorf("Redirects can only be followed when reading the whole dump")
+			os.Exit(1)
+		}
+
+		multistreamTitles = PersonTitleRegExp
+
+		if expr := viper.GetString("multistream-titles"); expr != "" {
+			if multistreamTitles, err = regexp.Compile(expr); err != nil {
+				logrus.Errorf("Invalid multistream titles: %v", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	// Decompress in parallel
+	workers := 1
+	if viper.GetBool("parallel-decompress") {
+		workers = viper.GetInt("decompress-workers")
+	}
+
+	// Write raw templates (one per line, after the page title) and their fields (after the page title and the
+	// index of the template on the page) to debug extraction
+	var templateDebug func(string, string)
+	var fieldDebug func(string, int, string, string)
+	var debugFiles []*DebugWriter
+
+	if path := viper.GetString("template-debug"); path != "" {
+		dw, err := CreateDebugFile(path)
+		if err != nil {
+			logrus.Errorf("Unable to create template debug file: %v", err)
+			os.Exit(1)
+		}
+
+		debugFiles = append(debugFiles, dw)
+
+		templateDebug = func(title string, template string) {
+			dw.WriteLine(title, template)
+		}
+	}
+
+	if path := viper.GetString("field-debug"); path != "" {
+		dw, err := CreateDebugFile(path)
+		if err != nil {
+			logrus.Errorf("Unable to create field debug file: %v", err)
+			os.Exit(1)
+		}
+
+		debugFiles = append(debugFiles, dw)
+
+		fieldDebug = func(title string, index int, key string, value string) {
+			dw.WriteLine(title, strconv.Itoa(index), key, value)
+		}
+	}
+
+	var parsers []*wordlist.DumpParser
+	var bars []*mpb.Bar
+	var sources []ManifestSource
+
+	if csvInput := viper.GetString("csv-input"); csvInput != "" {
+		// Read names from CSV/TSV file instead
+		parseCSV(csvInput, p, stopwords, transforms, emit)
+		sources = []ManifestSource{{Name: csvInput}}
+	} else if sqliteInput := viper.GetString("sqlite-input"); sqliteInput != "" {
+		// Read page texts from SQLite database instead
+		parsers = []*wordlist.DumpParser{{
+			Language:  wordlist.Languages[codes[0]],
+			Stopwords: stopwords,
+			Combine:   viper.GetBool("combine"),
+			Sample:    viper.GetInt("sample"),
+			Reservoir: newReservoir(),
+			Deadline:  deadline,
+			CamelCase: viper.GetBool("split-camel-case"),
+			Joined:    viper.GetString("firstname-tokens") == "joined",
+			Words:     wordsPerName(),
+
+			Transforms:    transforms,
+			TitleFallback: viper.GetBool("use-title-fallback"),
+			BirthYears:    birthYears,
+			TemplateDebug: templateDebug,
+			FieldDebug:    fieldDebug,
+		}}
+
+		parsers[0].Progress = newProgress(codes[0], parsers[0])
+		parsers[0].Counters = &wordlist.ParseCounters{}
+		parseSQLite(sqliteInput, p, parsers[0], emit)
+		sources = []ManifestSource{{Language: codes[0], Name: sqliteInput}}
+	} else if viper.GetBool("wikidata") {
+		// Query given names from Wikidata instead
+		wp := &WikidataParser{
+			Endpoint:  viper.GetString("wikidata-url"),
+			Language:  codes[0],
+			PageSize:  viper.GetInt("wikidata-page-size"),
+			Stopwords: stopwords,
+
+			Transforms: transforms,
+		}
+
+		if err := wp.Parse(client, emit); err != nil {
+			logrus.Errorf("Unable to query Wikidata: %v", err)
+			os.Exit(1)
+		}
+
+		logrus.Infof("Fetched %d names in %d queries from Wikidata", wp.Names, wp.Pages)
+		sources = []ManifestSource{{Language: codes[0], Name: wp.Endpoint}}
+	} else if wikidataDump := viper.GetString("wikidata-dump"); wikidataDump != "" {
+		// Read given names from Wikidata JSON dump instead
+		parseWikidataDump(wikidataDump, codes[0], p, stopwords, transforms, emit)
+		sources = []ManifestSource{{Language: codes[0], Name: wikidataDump}}
+	} else {
+		parsers = make([]*wordlist.DumpParser, len(codes))
+		bars = make([]*mpb.Bar, len(codes))
+		sources = make([]ManifestSource, len(codes))
+		pwg := &sync.WaitGroup{}
+
+		// Pause parsing while saving checkpoints
+		lock := &sync.RWMutex{}
+		cpSources := make([]CheckpointSource, len(codes))
+
+		saveCheckpoint := func() {
+			lock.Lock()
+			cp := &Checkpoint{
+				Sources:    make([]CheckpointSource, 0, len(codes)),
+				Options:    checkpointOptions,
+				Firstnames: firstnameHist.Snapshot(),
+				Combined:   combinedHist.Snapshot(),
+			}
+
+			for i := range cpSources {
+				if cpSources[i].Name != "" {
+					cpSources[i].Pages = parsers[i].Pages
+					cp.Sources = append(cp.Sources, cpSources[i])
+				}
+			}
+			lock.Unlock()
+
+			if err := cp.Save(checkpointPath); err != nil {
+				logrus.Errorf("Unable to save checkpoint: %v", err)
+			} else {
+				logrus.Debugf("Saved checkpoint to %s", checkpointPath)
+			}
+		}
+
+		for i, code := range codes {
+			parsers[i] = &wordlist.DumpParser{
+				Language:  wordlist.Languages[code],
+				Stopwords: stopwords,
+				Combine:   viper.GetBool("combine"),
+				Namespace: strconv.Itoa(viper.GetInt("namespace")),
+				Sample:    viper.GetInt("sample"),
+				Reservoir: newReservoir(),
+				Deadline:  deadline,
+				CamelCase: viper.GetBool("split-camel-case"),
+				Joined:    viper.GetString("firstname-tokens") == "joined",
+				Words:     wordsPerName(),
+				Strict:    viper.GetBool("strict"),
+
+				Transforms:       transforms,
+				ExcludeRedirects: viper.GetBool("exclude-redirects"),
+				TitleFallback:    viper.GetBool("use-title-fallback"),
+				BirthYears:       birthYears,
+				TemplateDebug:    templateDebug,
+				FieldDebug:       fieldDebug,
+			}
+
+			parsers[i].Progress = newProgress(code, parsers[i])
+			parsers[i].Counters = &wordlist.ParseCounters{}
+
+			if checkpoint != nil {
+				parsers[i].Lock = lock
+			}
+
+			pwg.Add(1)
+			go func(i int, code string) {
+				defer pwg.Done()
+
+				// Collect redirects in a first pass
+				if viper.GetBool("redirect-follow") {
+					parsers[i].Redirects = collectRedirects(code, p, client, workers)
+				}
+
+				// Open Wikipedia dump (or only the streams with person pages)
+				var src io.ReadCloser
+				var info DumpInfo
+				var total int64
+
+				if multistreamTitles != nil {
+					src, info, total = OpenMultistream(wordlist.Languages[code], client, multistreamTitles)
+				} else {
+					src, info = OpenDump(wordlist.Languages[code], client)
+					total = info.Size
+				}
+
+				defer src.Close()
+
+				sources[i] = NewManifestSource(code, info)
+
+				// Skip pages processed before, but only in the identical dump
+				if checkpoint != nil {
+					cs := NewCheckpointSource(code, info)
+
+					if prev := checkpoint.Source(code); prev != nil {
+						if !prev.Matches(cs) {
+							logrus.Errorf("The %s dump changed since checkpoint %s was saved, remove it to start over", code, checkpointPath)
+							os.Exit(1)
+						}
+
+						parsers[i].Skip = prev.Pages
+					}
+
+					lock.Lock()
+					cpSources[i] = cs
+					lock.Unlock()
+				}
+
+				bars[i] = p.AddBar(total,
+					mpb.PrependDecorators(
+						decor.Name(code+" "),
+						decor.CountersKibiByte("% .2f / % .2f"),
+					),
+					mpb.AppendDecorators(
+						decor.Percentage(),
+						decor.Name(" | ETA: "),
+						NewETADecorator(),
+						NewParseDecorator(parsers[i].Counters),
+					),
+				)
+
+				pr := NewProgressReader(bars[i], src)
+
+				// Decompress
+				decr, err := NewDecompressReader(pr, viper.GetString("compression"), info.Name, workers)
+				if err != nil {
+					logrus.Errorf("Unable to decompress %s dump: %v", code, err)
+					os.Exit(1)
+				}
+
+				defer decr.Close()
+
+				// Streamed XML parsing
+				if err := parsers[i].Parse(decr, emit); err != nil {
+					logrus.Errorf("Unable to parse %s dump: %v", code, err)
+					os.Exit(1)
+				}
+
+				if f := parsers[i].Failures; f > 0 {
+					logrus.Warnf("Skipped %d of %d pages in %s dump that could not be decoded (use --strict to abort instead)", f, parsers[i].Pages, code)
+				}
+			}(i, code)
+		}
+
+		// Save checkpoints periodically, and when interrupted
+		done := make(chan struct{})
+
+		if checkpoint != nil {
+			outputMu.Lock()
+			onInterrupt = saveCheckpoint
+			outputMu.Unlock()
+
+			go func() {
+				ticker := time.NewTicker(viper.GetDuration("checkpoint-interval"))
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ticker.C:
+						saveCheckpoint()
+					case <-done:
+						return
+					}
+				}
+			}()
+		}
+
+		pwg.Wait()
+		close(done)
+
+		// Remove checkpoint of the finished run
+		if checkpoint != nil {
+			outputMu.Lock()
+			onInterrupt = nil
+			outputMu.Unlock()
+
+			if err := os.Remove(checkpointPath); err != nil && !os.IsNotExist(err) {
+				logrus.Warnf("Unable to remove checkpoint: %v", err)
+			}
+		}
+	}
+
+	for _, dw := range debugFiles {
+		if err := dw.Close(); err != nil {
+			logrus.Errorf("Unable to write debug file: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Output names within the frequency band
+	if deferred {
+		if percentile > 0 {
+			logrus.Infof("Keeping first names occuring at least %d times (percentile %g)", minCount(firstnameHist.Snapshot()), percentile)
+		}
+
+		sendHistograms(cntMax)
+	}
+
+	// Clean up output go routine
+	close(ch)
+	wg.Wait()
+
+	// Leave closing output to an interruption in progress (which exits), or stop handling interruptions
+	outputMu.Lock()
+	if interrupted {
+		outputMu.Unlock()
+		select {}
+	}
+
+	finished = true
+	outputMu.Unlock()
+
+	signal.Stop(sigs)
+
+	if stats.Err != nil {
+		logOutputError(stats.Err)
+		closePartialOutput()
+		os.Exit(1)
+	}
+
+	if outCloser != nil {
+		if err := outCloser.Close(); err != nil {
+			logrus.Errorf("Unable to finish output: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	logrus.Debugf("Output channel was full %d times, stalling parsing for %s", stalls, time.Duration(stallTime))
+
+	// Report coverage of person data
+	for i, dp := range parsers {
+		logrus.Infof(
+			"Processed %d pages in %s dump: %d with person data, %d without, %d with malformed person data",
+			dp.Pages, codes[i], dp.PersonPages, dp.Pages-dp.PersonPages-dp.MalformedPages, dp.MalformedPages,
+		)
+	}
+
+	// Write frequencies
+	if path := viper.GetString("freq-out"); path != "" {
+		f, err := CreateFile(path)
+		if err != nil {
+			logrus.Errorf("Unable to create frequency file: %v", err)
+			os.Exit(1)
+		}
+
+		err = WriteFrequencies(f, selectNames(firstnameHist.Snapshot(), cntMax), viper.GetString("freq-format"))
+		f.Close()
+
+		if err != nil {
+			logrus.Errorf("Unable to write frequency file: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Write hashcat masks (of the case variants, digits, and special characters of entries)
+	if path := viper.GetString("mask-out"); path != "" {
+		f, err := CreateFile(path)
+		if err != nil {
+			logrus.Errorf("Unable to create mask file: %v", err)
+			os.Exit(1)
+		}
+
+		masks, skipped := RankMasks(selectNames(firstnameHist.Snapshot(), cntMax), caseVariants)
+		if skipped > 0 {
+			logrus.Infof("Skipped %d names with characters not covered by masks", skipped)
+		}
+
+		err = WriteMasks(f, masks, viper.GetInt("digits"), viper.GetString("special-chars"))
+		f.Close()
+
+		if err != nil {
+			logrus.Errorf("Unable to write mask file: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Write manifest
+	if path := viper.GetString("manifest"); path != "" {
+		f, err := CreateFile(path)
+		if err != nil {
+			logrus.Errorf("Unable to create manifest file: %v", err)
+			os.Exit(1)
+		}
+
+		err = WriteManifest(f, &Manifest{
+			Version: cmd.Root().Version,
+			Created: time.Now().UTC(),
+			Sources: sources,
+			Flags:   ManifestFlags(cmd.Flags()),
+			Names:   stats.Names,
+			Lines:   stats.Lines,
+		})
+		f.Close()
+
+		if err != nil {
+			logrus.Errorf("Unable to write manifest file: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Report throughput
+	if benchmark {
+		elapsed := time.Since(start).Seconds()
+
+		var pages, bytes, names int64
+		for i := range parsers {
+			pages += int64(parsers[i].Pages)
+			names += int64(parsers[i].Names)
+		}
+
+		for i := range bars {
+			bytes += bars[i].Current()
+		}
+
+		// Printed to stdout (not logged), so it's also shown with --quiet
+		fmt.Printf("Processed %d pages, %d bytes, and %d names in %.2fs\n", pages, bytes, names, elapsed)
+		fmt.Printf(
+			"Throughput: %.2f pages/s, %.2f MiB/s, %.2f names/s\n",
+			float64(pages)/elapsed,
+			float64(bytes)/elapsed/(1<<20),
+			float64(names)/elapsed,
+		)
+	}
+
+	// Fail if too few names were written
+	if min := viper.GetInt("require-min-names"); !benchmark && stats.Names < min {
+		// Count names of both histograms written (combined names only with --combine)
+		extracted := len(firstnameHist.Snapshot()) + len(combinedHist.Snapshot())
+
+		switch {
+		case extracted == 0:
+			logrus.Errorf("Wrote %d names, but at least %d are required: no names were extracted from the input", stats.Names, min)
+		case percentile > 0:
+			logrus.Errorf(
+				"Wrote %d names, but at least %d are required: %d names were extracted, but too few are above percentile %g (see --count-percentile)",
+				stats.Names, min, extracted, percentile,
+			)
+		case cntMax > 0:
+			logrus.Errorf(
+				"Wrote %d names, but at least %d are required: %d names were extracted, but too few occur %d to %d times (see --count and --count-max)",
+				stats.Names, min, extracted, cnt, cntMax,
+			)
+		default:
+			logrus.Errorf(
+				"Wrote %d names, but at least %d are required: %d names were extracted, but too few occur at least %d times (see --count)",
+				stats.Names, min, extracted, cnt,
+			)
+		}
+
+		os.Exit(2)
+	}
+}
+
+// collectRedirects reads the whole dump of the given language and returns the number of redirects to each page.
+func collectRedirects(code string, p *mpb.Progress, client *http.Client, workers int) map[string]int {
+	src, info := OpenDump(wordlist.Languages[code], client)
+	defer src.Close()
+
+	bar := p.AddBar(info.Size,
+		mpb.PrependDecorators(
+			decor.Name(code+" redirects "),
+			decor.CountersKibiByte("% .2f / % .2f"),
+		),
+		mpb.AppendDecorators(
+			decor.Percentage(),
+			decor.Name(" | ETA: "),
+			NewETADecorator(),
+		),
+		mpb.BarRemoveOnComplete(),
+	)
+
+	decr, err := NewDecompressReader(NewProgressReader(bar, src), viper.GetString("compression"), info.Name, workers)
+	if err != nil {
+		logrus.Errorf("Unable to decompress %s dump: %v", code, err)
+		os.Exit(1)
+	}
+
+	defer decr.Close()
+
+	redirects, err := CollectRedirects(decr, strconv.Itoa(viper.GetInt("namespace")))
+	if err != nil {
+		logrus.Errorf("Unable to collect redirects of %s dump: %v", code, err)
+		os.Exit(1)
+	}
+
+	// Make sure the bar is removed even if the size was unknown
+	bar.SetTotal(bar.Current(), true)
+
+	counts := ResolveRedirects(redirects)
+	logrus.Infof("Found %d redirects to %d pages in %s dump", len(redirects), len(counts), code)
+
+	return counts
+}
+
+// parseCSV reads names from the given CSV/TSV file and passes them to emit.
+func parseCSV(path string, p *mpb.Progress, stopwords wordlist.Stopwords, transforms wordlist.NameTransforms, emit func(wordlist.Name)) {
+	f, err := os.Open(path)
+	if err != nil {
+		logrus.Errorf("Unable to open CSV file: %v", err)
+		os.Exit(1)
+	}
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		logrus.Errorf("Unable to stat CSV file: %v", err)
+		os.Exit(1)
+	}
+
+	bar := p.AddBar(fi.Size(),
+		mpb.PrependDecorators(decor.CountersKibiByte("% .2f / % .2f")),
+		mpb.AppendDecorators(
+			decor.Percentage(),
+			decor.Name(" | ETA: "),
+			NewETADecorator(),
+		),
+	)
+
+	cp := &CSVParser{
+		Column:    viper.GetInt("name-column"),
+		Delimiter: CSVDelimiter(path),
+		Header:    viper.GetBool("csv-header"),
+		Stopwords: stopwords,
+		Joined:    viper.GetString("firstname-tokens") == "joined",
+		Words:     wordsPerName(),
+
+		Transforms: transforms,
+	}
+
+	if err := cp.Parse(NewProgressReader(bar, f), emit); err != nil {
+		logrus.Errorf("Unable to parse CSV file: %v", err)
+		os.Exit(1)
+	}
+}
+
+// wordsPerName returns the number of first names counted on their own (-1 for all of them).
+func wordsPerName() int {
+	if viper.GetBool("emit-all-parts") {
+		return -1
+	}
+
+	return viper.GetInt("words-per-name")
+}
+
+// parseWikidataDump reads given names in the given language from the Wikidata JSON dump at path and passes them
+// to emit.
+func parseWikidataDump(path string, code string, p *mpb.Progress, stopwords wordlist.Stopwords, transforms wordlist.NameTransforms, emit func(wordlist.Name)) {
+	f, err := os.Open(path)
+	if err != nil {
+		logrus.Errorf("Unable to open Wikidata dump: %v", err)
+		os.Exit(1)
+	}
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		logrus.Errorf("Unable to stat Wikidata dump: %v", err)
+		os.Exit(1)
+	}
+
+	bar := p.AddBar(fi.Size(),
+		mpb.PrependDecorators(decor.CountersKibiByte("% .2f / % .2f")),
+		mpb.AppendDecorators(
+			decor.Percentage(),
+			decor.Name(" | ETA: "),
+			NewETADecorator(),
+		),
+	)
+
+	decr, err := NewDecompressReader(NewProgressReader(bar, f), viper.GetString("compression"), path, 1)
+	if err != nil {
+		logrus.Errorf("Unable to decompress Wikidata dump: %v", err)
+		os.Exit(1)
+	}
+
+	defer decr.Close()
+
+	wp := &WikidataDumpParser{
+		Language:  code,
+		Stopwords: stopwords,
+
+		Transforms: transforms,
+	}
+
+	if err := wp.Parse(decr, emit); err != nil {
+		logrus.Errorf("Unable to parse Wikidata dump: %v", err)
+		os.Exit(1)
+	}
+
+	logrus.Infof("Read %d entities from Wikidata dump: %d humans with given names, %d names", wp.Entities, wp.Humans, wp.Names)
+}
+
+// parseSQLite reads page texts from the given SQLite database and passes the names found to emit.
+func parseSQLite(path string, p *mpb.Progress, dp *wordlist.DumpParser, emit func(wordlist.Name)) {
+	if _, err := os.Stat(path); err != nil {
+		logrus.Errorf("Unable to open SQLite database: %v", err)
+		os.Exit(1)
+	}
+
+	db, err := OpenSQLite(path)
+	if err != nil {
+		logrus.Errorf("Unable to open SQLite database: %v", err)
+		os.Exit(1)
+	}
+
+	defer db.Close()
+
+	table := viper.GetString("sqlite-table")
+
+	rows, err := wordlist.CountSQLite(db, table)
+	if err != nil {
+		logrus.Errorf("Unable to read SQLite database: %v", err)
+		os.Exit(1)
+	}
+
+	bar := p.AddBar(rows,
+		mpb.PrependDecorators(decor.CountersNoUnit("%d / %d pages")),
+		mpb.AppendDecorators(
+			decor.Percentage(),
+			decor.Name(" | ETA: "),
+			NewETADecorator(),
+			NewParseDecorator(dp.Counters),
+		),
+	)
+
+	prev := time.Now()
+	step := func() {
+		next := time.Now()
+		bar.IncrBy(1, next.Sub(prev))
+		prev = next
+	}
+
+	if err := dp.ParseSQLite(db, table, step, emit); err != nil {
+		logrus.Errorf("Unable to parse SQLite database: %v", err)
+		os.Exit(1)
+	}
+}
+
+// DumpInfo describes the source of a Wikipedia dump.
+type DumpInfo struct {
+	Name         string    // Path or URL of the dump
+	Size         int64     // Size in bytes (-1 if unknown)
+	LastModified time.Time // Time of last modification (zero if unknown)
+	ETag         string    // Entity tag of the download (empty if unknown)
+}
+
+// OpenDump opens the Wikipedia dump for the given language, either from the local file or URL given by the
+// user or from the default URL of the language. It returns the dump and information about its source.
+func OpenDump(lang *wordlist.Language, client *http.Client) (io.ReadCloser, DumpInfo) {
+	// Read from local file
+	if dumpFile := viper.GetString("dump-file"); dumpFile != "" {
+		f, err := os.Open(dumpFile)
+		if err != nil {
+			logrus.Errorf("Unable to open dump file: %v", err)
+			os.Exit(1)
+		}
+
+		fi, err := f.Stat()
+		if err != nil {
+			logrus.Errorf("Unable to stat dump file: %v", err)
+			os.Exit(1)
+		}
+
+		br := bufio.NewReader(f)
+		if err := CheckCompression(br, viper.GetString("compression"), dumpFile); err != nil {
+			logrus.Errorf("Unable to read dump file %s: %v", dumpFile, err)
+			os.Exit(1)
+		}
+
+		return bufferedReadCloser{br, f}, DumpInfo{Name: dumpFile, Size: fi.Size(), LastModified: fi.ModTime()}
+	}
+
+	// Download Wikipedia Dump
+	dumpUrl := viper.GetString("dump-url")
+	if dumpUrl == "" {
+		dumpUrl = lang.DumpURL
+	}
+
+	// Never log credentials given in the URL
+	dumpName := RedactURL(dumpUrl)
+
+	req, err := NewAuthRequest(http.MethodGet, dumpUrl, viper.GetString("bearer-token"))
+	if err != nil {
+		logrus.Errorf("Unable to fetch dump %s: %v", dumpName, err)
+		os.Exit(1)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		logrus.Errorf("Unable to fetch abstract index: %v", err)
+		os.Exit(1)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		logrus.Errorf("Unable to fetch dump %s: %s", dumpName, resp.Status)
+		os.Exit(1)
+	}
+
+	// Fail early if a mirror serves something else (i.e. an HTML error page)
+	br := bufio.NewReader(resp.Body)
+	if err := CheckCompression(br, viper.GetString("compression"), req.URL.Path); err != nil {
+		logrus.Errorf("Unable to read dump %s: %v (HTTP status %s, content type %q)", dumpName, err, resp.Status, resp.Header.Get("Content-Type"))
+		os.Exit(1)
+	}
+
+	info := DumpInfo{Name: dumpName, Size: resp.ContentLength, ETag: resp.Header.Get("ETag")}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.LastModified = t
+	}
+
+	return bufferedReadCloser{br, resp.Body}, info
+}
+
+// bufferedReadCloser reads through a buffered reader, but closes the underlying reader.
+type bufferedReadCloser struct {
+	*bufio.Reader
+	io.Closer
+}
+
+// OpenMultistream opens the multistream variant of the dump of the given language and reads its index. The
+// returned reader only yields the streams holding pages with titles matching titles, the total size of which
+// is returned as well.
+func OpenMultistream(lang *wordlist.Language, client *http.Client, titles *regexp.Regexp) (io.ReadCloser, DumpInfo, int64) {
+	var src io.ReaderAt
+	var info DumpInfo
+	var indexBase string
+
+	if dumpFile := viper.GetString("dump-file"); dumpFile != "" {
+		// Read from local file
+		f, err := os.Open(dumpFile)
+		if err != nil {
+			logrus.Errorf("Unable to open dump file: %v", err)
+			os.Exit(1)
+		}
+
+		fi, err := f.Stat()
+		if err != nil {
+			logrus.Errorf("Unable to stat dump file: %v", err)
+			os.Exit(1)
+		}
+
+		src, indexBase = f, dumpFile
+		info = DumpInfo{Name: dumpFile, Size: fi.Size(), LastModified: fi.ModTime()}
+	} else {
+		// Download parts of Wikipedia dump
+		dumpUrl := viper.GetString("dump-url")
+		if dumpUrl == "" {
+			dumpUrl = MultistreamURL(lang.DumpURL)
+		}
+
+		req, err := NewAuthRequest(http.MethodHead, dumpUrl, viper.GetString("bearer-token"))
+		if err != nil {
+			logrus.Errorf("Unable to fetch multistream dump: %v", err)
+			os.Exit(1)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			logrus.Errorf("Unable to fetch multistream dump: %v", err)
+			os.Exit(1)
+		}
+
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			logrus.Errorf("Unable to fetch multistream dump: %s", resp.Status)
+			os.Exit(1)
+		}
+
+		// Make sure the server supports range requests
+		hr := &HTTPRangeReader{Client: client, URL: dumpUrl, BearerToken: viper.GetString("bearer-token")}
+		if _, err := hr.ReadAt(make([]byte, 1), 0); err != nil {
+			logrus.Errorf("Unable to read parts of multistream dump: %v", err)
+			os.Exit(1)
+		}
+
+		src, indexBase = hr, dumpUrl
+		info = DumpInfo{Name: RedactURL(dumpUrl), Size: resp.ContentLength, ETag: resp.Header.Get("ETag")}
+
+		if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+			info.LastModified = t
+		}
+	}
+
+	if info.Size <= 0 {
+		logrus.Errorf("Unable to determine size of multistream dump %s", info.Name)
+		os.Exit(1)
+	}
+
+	// Read index
+	indexName := viper.GetString("multistream-index")
+	if indexName == "" {
+		indexName = MultistreamIndexName(indexBase)
+	}
+
+	var index io.ReadCloser
+
+	if strings.HasPrefix(indexName, "http://") || strings.HasPrefix(indexName, "https://") {
+		req, err := NewAuthRequest(http.MethodGet, indexName, viper.GetString("bearer-token"))
+		if err != nil {
+			logrus.Errorf("Unable to fetch multistream index: %v", err)
+			os.Exit(1)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			logrus.Errorf("Unable to fetch multistream index: %v", err)
+			os.Exit(1)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			logrus.Errorf("Unable to fetch multistream index: %s", resp.Status)
+			os.Exit(1)
+		}
+
+		index = resp.Body
+	} else {
+		f, err := os.Open(indexName)
+		if err != nil {
+			logrus.Errorf("Unable to open multistream index: %v", err)
+			os.Exit(1)
+		}
+
+		index = f
+	}
+
+	defer index.Close()
+
+	decr, err := NewDecompressReader(index, "auto", indexName, 1)
+	if err != nil {
+		logrus.Errorf("Unable to decompress multistream index: %v", err)
+		os.Exit(1)
+	}
+
+	ranges, pages, err := ReadMultistreamIndex(decr, titles, info.Size)
+	decr.Close()
+
+	if err != nil {
+		logrus.Errorf("Unable to read multistream index %s: %v", indexName, err)
+		os.Exit(1)
+	}
+
+	var total int64
+	for _, rng := range ranges {
+		total += rng.End - rng.Offset
+	}
+
+	logrus.Infof(
+		"Reading %d pages with matching titles from %.2f MiB (%.1f %%) of %s",
+		pages, float64(total)/(1<<20), 100*float64(total)/float64(info.Size), info.Name,
+	)
+
+	return NewMultistreamReader(src, ranges, info.Size), info, total
+}
+
+// NewETADecorator returns the ETA decorator selected by the user.
+func NewETADecorator() decor.Decorator {
+	if viper.GetString("progress-eta") == "linear" {
+		return decor.AverageETA(decor.ET_STYLE_HHMMSS)
+	}
+
+	avg := NewSeededAverage(viper.GetFloat64("progress-window"), viper.GetInt("progress-seed"))
+	return decor.MovingAverageETA(decor.ET_STYLE_HHMMSS, avg, nil)
+}
+
+// ParseDecorator is a progress bar decorator showing the number of pages parsed (per second) and names found.
+type ParseDecorator struct {
+	decor.WC
+	counters *wordlist.ParseCounters // Counters of the parser
+	start    time.Time               // Time parsing started
+}
+
+// NewParseDecorator returns a decorator showing the given parser counters.
+func NewParseDecorator(counters *wordlist.ParseCounters) decor.Decorator {
+	d := &ParseDecorator{counters: counters, start: time.Now()}
+	d.Init()
+
+	return d
+}
+
+// Decor implements decor.Decorator.
+func (d *ParseDecorator) Decor(st *decor.Statistics) string {
+	pages := atomic.LoadInt64(&d.counters.Pages)
+	rate := float64(pages) / time.Since(d.start).Seconds()
+
+	return d.FormatMsg(fmt.Sprintf(" | %d pages (%.0f/s), %d names", pages, rate, atomic.LoadInt64(&d.counters.Names)))
+}
+
+// logOutputError logs an error writing output, pointing out a full disk.
+func logOutputError(err error) {
+	if errors.Is(err, syscall.ENOSPC) {
+		logrus.Errorf("Unable to write output, the disk is full: %v", err)
+	} else {
+		logrus.Errorf("Unable to write output: %v", err)
+	}
+}
+
+// ProjectOutput returns the projected number of entries and bytes written for the given number of names of
+// average length. In combine mode, each first name is assumed to be combined with one last name.
+func ProjectOutput(opts *wordlist.OutputOptions, names int, combine bool) (int64, int64) {
+	bases := int64(1)
+	if combine {
+		bases += 2 * int64(len(opts.CombineSeparators))
+	}
+
+	if opts.Reverse {
+		bases *= 2
+	}
+
+	le := int64(len(opts.LineEnding))
+	nl := int64(AverageNameLength + len(opts.NamePrefix) + len(opts.NameSuffix))
+	if opts.Pattern != nil {
+		nl += int64(opts.Pattern.LiteralLength())
+	}
+
+	cases := int64(len(opts.Cases))
+	if opts.PreserveCase {
+		cases++
+	}
+
+	if opts.NamesOnly {
+		return int64(names) * bases * cases, int64(names) * bases * cases * (nl + le)
+	}
+
+	// Entries and bytes for a single word with all digit and special character suffixes
+	digitCombs := wordlist.SuffixCombinations(opts.Digits, opts.KeyboardWalks)
+	nd, nc := int64(len(digitCombs)), int64(len(opts.SpecialChars)+1)
+
+	var sd, sc int64
+	for _, d := range digitCombs {
+		sd += int64(len(d))
+	}
+
+	for _, c := range opts.SpecialChars {
+		sc += int64(len(string(c)))
+	}
+
+	lines := cases * nd * nc
+	bytes := cases * (nd*nc*(nl+le) + nc*sd + nd*sc)
+
+	// Limit variants per name
+	if max := int64(opts.MaxVariants); max > 0 && lines*bases > max {
+		bytes = bytes * max / (lines * bases)
+		lines = max / bases
+	}
+
+	return int64(names) * bases * lines, int64(names) * bases * bytes
+}
+
+// DiscardRoutine drains the channel without generating any output.
+func DiscardRoutine(ch chan wordlist.Name, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for range ch {
 	}
 }
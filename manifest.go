@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Manifest describes how a wordlist was generated.
+type Manifest struct {
+	Version string                 `json:"version"` // Version of names-wordlist
+	Created time.Time              `json:"created"` // Time the wordlist was finished
+	Sources []ManifestSource       `json:"sources"` // Sources the names were read from
+	Flags   map[string]interface{} `json:"flags"`   // Effective value of all flags
+	Names   int                    `json:"names"`   // Number of names written
+	Lines   int64                  `json:"lines"`   // Number of entries written
+}
+
+// ManifestSource describes a single source of names.
+type ManifestSource struct {
+	Language     string     `json:"language,omitempty"`      // Language code
+	Name         string     `json:"name"`                    // Path or URL
+	Size         int64      `json:"size,omitempty"`          // Size in bytes
+	LastModified *time.Time `json:"last-modified,omitempty"` // Time of last modification
+}
+
+// NewManifestSource returns the manifest source for the dump of the given language.
+func NewManifestSource(language string, info DumpInfo) ManifestSource {
+	src := ManifestSource{Language: language, Name: info.Name}
+
+	if info.Size > 0 {
+		src.Size = info.Size
+	}
+
+	if !info.LastModified.IsZero() {
+		t := info.LastModified.UTC()
+		src.LastModified = &t
+	}
+
+	return src
+}
+
+// SecretFlags holds flags whose values are masked in manifests.
+var SecretFlags = map[string]bool{
+	"bearer-token": true,
+}
+
+// ManifestFlags returns the effective values of all flags, including those set by config file, environment,
+// or profile. Secrets are masked, and so are passwords in URLs.
+func ManifestFlags(flags *pflag.FlagSet) map[string]interface{} {
+	values := map[string]interface{}{}
+
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Name == "help" || f.Name == "version" {
+			return
+		}
+
+		v := viper.Get(f.Name)
+
+		if s, ok := v.(string); ok {
+			if SecretFlags[f.Name] && s != "" {
+				v = "xxxxx"
+			} else if strings.Contains(s, "://") {
+				v = RedactURL(s)
+			}
+		}
+
+		values[f.Name] = v
+	})
+
+	return values
+}
+
+// WriteManifest writes m to w as indented JSON.
+func WriteManifest(w io.Writer, m *Manifest) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(m)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func TestManifestFlagsMasksSecrets(t *testing.T) {
+	defer viper.Reset()
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("dump-url", "", "")
+	flags.String("multistream-index", "", "")
+	flags.String("bearer-token", "", "")
+	flags.Int("digits", 4, "")
+
+	err := flags.Parse([]string{
+		"--dump-url", "https://user:pw@mirror.example.com/dewiki.xml.bz2",
+		"--multistream-index", "https://user:pw@mirror.example.com/index.txt.bz2",
+		"--bearer-token", "SECRET123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	viper.BindPFlags(flags)
+
+	var buf bytes.Buffer
+	if err := WriteManifest(&buf, &Manifest{Flags: ManifestFlags(flags)}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"SECRET123", "user:pw", ":pw@"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("manifest contains secret %q:\n%s", secret, buf.String())
+		}
+	}
+
+	if !strings.Contains(buf.String(), "mirror.example.com/dewiki.xml.bz2") {
+		t.Errorf("manifest is missing the dump URL:\n%s", buf.String())
+	}
+}
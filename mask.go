@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+)
+
+// MaskCount is a hashcat mask with the number of name occurrences fitting it.
+type MaskCount struct {
+	Mask  string // Mask of the name (i.e. "?u?l?l?l")
+	Count int    // Number of occurrences of names fitting the mask
+}
+
+// NameMask returns the hashcat mask of s (i.e. "?u?l?l?l" for "Anna"), or false if s holds characters not covered
+// by the built-in charsets (i.e. non-ASCII letters).
+func NameMask(s string) (string, bool) {
+	var sb strings.Builder
+
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z':
+			sb.WriteString("?l")
+		case r >= 'A' && r <= 'Z':
+			sb.WriteString("?u")
+		case r >= '0' && r <= '9':
+			sb.WriteString("?d")
+		case r >= ' ' && r <= '~':
+			sb.WriteString("?s")
+		default:
+			return "", false
+		}
+	}
+
+	return sb.String(), true
+}
+
+// RankMasks returns the masks of the given names in the given case variants, sorted by the number of occurrences
+// of names fitting them (descending). It also returns the number of names skipped as they don't fit any mask.
+func RankMasks(ncs []NameCount, cases []string) ([]MaskCount, int) {
+	counts := make(map[string]int)
+	skipped := 0
+
+	for _, nc := range ncs {
+		// Count each mask once per name, even if multiple case variants share it
+		seen := make(map[string]bool, len(cases))
+
+		for _, c := range cases {
+			mask, ok := NameMask(wordlist.ApplyCase(nc.Name, c))
+			if !ok {
+				skipped++
+				break
+			}
+
+			if !seen[mask] {
+				seen[mask] = true
+				counts[mask] += nc.Count
+			}
+		}
+	}
+
+	mcs := make([]MaskCount, 0, len(counts))
+	for mask, count := range counts {
+		mcs = append(mcs, MaskCount{Mask: mask, Count: count})
+	}
+
+	sort.Slice(mcs, func(i, j int) bool {
+		if mcs[i].Count != mcs[j].Count {
+			return mcs[i].Count > mcs[j].Count
+		}
+
+		return mcs[i].Mask < mcs[j].Mask
+	})
+
+	return mcs, skipped
+}
+
+// WriteMasks writes the given name masks to w in hashcat's .hcmask format, each followed by zero to digits ?d
+// (fewest first) and, if specialChars isn't empty, also by a custom charset ?1 of them.
+func WriteMasks(w io.Writer, mcs []MaskCount, digits int, specialChars string) error {
+	bw := bufio.NewWriter(w)
+
+	// Custom charset of special characters (escaping commas and question marks)
+	suffixes := []string{""}
+	charset := ""
+
+	if specialChars != "" {
+		suffixes = append(suffixes, "?1")
+		charset = strings.NewReplacer(",", "\\,", "?", "??").Replace(specialChars) + ","
+	}
+
+	for _, mc := range mcs {
+		for d := 0; d <= digits; d++ {
+			for _, suffix := range suffixes {
+				mask := mc.Mask + strings.Repeat("?d", d) + suffix
+				if suffix != "" {
+					mask = charset + mask
+				}
+
+				fmt.Fprintln(bw, mask)
+			}
+		}
+	}
+
+	return bw.Flush()
+}
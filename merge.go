@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// merge is called for the "merge" sub command.
+func merge(cmd *cobra.Command, args []string) {
+	output, _ := cmd.Flags().GetString("output")
+	sorted, _ := cmd.Flags().GetBool("sort")
+
+	// Refuse to overwrite an input (it's truncated before being read otherwise)
+	if output != "-" {
+		if ofi, err := os.Stat(output); err == nil {
+			for _, path := range args {
+				if fi, err := os.Stat(path); err == nil && os.SameFile(ofi, fi) {
+					logrus.Errorf("Unable to merge into %s: it's also an input wordlist", output)
+					os.Exit(1)
+				}
+			}
+		}
+	}
+
+	// Open output file
+	out, err := CreateFile(output)
+	if err != nil {
+		logrus.Errorf("Unable to create output file: %v", err)
+		os.Exit(1)
+	}
+
+	defer out.Close()
+
+	w := bufio.NewWriter(out)
+
+	// Read all input files
+	seen := make(map[string]struct{})
+
+	for _, path := range args {
+		f, err := os.Open(path)
+		if err != nil {
+			logrus.Errorf("Unable to open wordlist: %v", err)
+			os.Exit(1)
+		}
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if _, ok := seen[line]; ok {
+				continue
+			}
+
+			seen[line] = struct{}{}
+
+			// Write unsorted lines right away
+			if !sorted {
+				w.WriteString(line + "\n")
+			}
+		}
+
+		f.Close()
+
+		if err := scanner.Err(); err != nil {
+			logrus.Errorf("Unable to read wordlist %s: %v", path, err)
+			os.Exit(1)
+		}
+	}
+
+	// Write sorted lines
+	if sorted {
+		lines := make([]string, 0, len(seen))
+		for line := range seen {
+			lines = append(lines, line)
+		}
+
+		sort.Strings(lines)
+
+		for _, line := range lines {
+			w.WriteString(line + "\n")
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		logrus.Errorf("Unable to write output file: %v", err)
+		os.Exit(1)
+	}
+
+	logrus.Infof("Merged %d files into %d unique lines", len(args), len(seen))
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "names-wordlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	a := filepath.Join(dir, "a.lst")
+	b := filepath.Join(dir, "b.lst")
+
+	for path, data := range map[string]string{a: "john\nanna\n", b: "anna\nadam\n"} {
+		if err := ioutil.WriteFile(path, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := filepath.Join(dir, "merged.lst")
+	runCommand(t, "merge", "--sort", "-o", output, a, b)
+
+	data, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatalf("unable to read output: %v", err)
+	}
+
+	if got, want := string(data), "adam\nanna\njohn\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Merging into an input (even by another path) is refused, leaving it untouched
+	out := runFailingCommand(t, "merge", "-o", filepath.Join(dir, ".", "a.lst"), a, b)
+	if !strings.Contains(out, "also an input wordlist") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	if data, err := ioutil.ReadFile(a); err != nil || string(data) != "john\nanna\n" {
+		t.Errorf("input changed to %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PersonTitleRegExp matches page titles that look like the name of a person ("Firstname Lastname"), optionally
+// followed by a disambiguation (i.e. "John Doe (Musiker)").
+var PersonTitleRegExp = regexp.MustCompile(`^\p{Lu}[\p{L}'.\-]*(?: \p{Lu}[\p{L}'.\-]*)+(?: \([^()]+\))?$`)
+
+// StreamRange is the byte range of one or more consecutive streams in a multistream dump.
+type StreamRange struct {
+	Offset int64 // Offset of the first stream
+	End    int64 // Offset after the last stream
+}
+
+// MultistreamURL returns the URL of the multistream variant of the given dump URL.
+func MultistreamURL(url string) string {
+	return strings.Replace(url, "pages-articles.xml.bz2", "pages-articles-multistream.xml.bz2", 1)
+}
+
+// MultistreamIndexName returns the path or URL of the index of the given multistream dump.
+func MultistreamIndexName(name string) string {
+	return strings.Replace(name, ".xml.bz2", "-index.txt.bz2", 1)
+}
+
+// ReadMultistreamIndex reads the index of a multistream dump of the given size ("offset:id:title" per line)
+// from r. It returns the ranges of all streams holding at least one page with a title matching titles (merging
+// consecutive streams up to ParallelSegmentMax bytes), and the number of matching pages.
+func ReadMultistreamIndex(r io.Reader, titles *regexp.Regexp, size int64) ([]StreamRange, int, error) {
+	var offsets []int64
+	var selected []bool
+	var pages int
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) < 3 {
+			return nil, 0, fmt.Errorf("invalid index entry in line %d", line)
+		}
+
+		offset, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid offset in line %d: %w", line, err)
+		}
+
+		if n := len(offsets); n == 0 || offsets[n-1] != offset {
+			offsets = append(offsets, offset)
+			selected = append(selected, false)
+		}
+
+		if titles.MatchString(parts[2]) {
+			selected[len(selected)-1] = true
+			pages++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("unable to read index: %w", err)
+	}
+
+	// Collect ranges of selected streams
+	var ranges []StreamRange
+
+	for i, offset := range offsets {
+		if !selected[i] {
+			continue
+		}
+
+		end := size
+		if i+1 < len(offsets) {
+			end = offsets[i+1]
+		}
+
+		if n := len(ranges); n > 0 && ranges[n-1].End == offset && end-ranges[n-1].Offset <= ParallelSegmentMax {
+			ranges[n-1].End = end
+		} else {
+			ranges = append(ranges, StreamRange{Offset: offset, End: end})
+		}
+	}
+
+	return ranges, pages, nil
+}
+
+// MultistreamReader reads the given ranges of a multistream dump one after another, resulting in a valid
+// multi-stream bzip2 file. Each range is read at once, so that only a single request is needed when reading
+// from an HTTPRangeReader. The final stream of the dump (closing the XML document) is left out.
+type MultistreamReader struct {
+	src    io.ReaderAt   // Multistream dump
+	ranges []StreamRange // Ranges not read yet
+	size   int64         // Size of the dump
+	buf    []byte        // Data of the current range not read yet
+}
+
+// NewMultistreamReader returns a reader for the given ranges of src, which is size bytes long.
+func NewMultistreamReader(src io.ReaderAt, ranges []StreamRange, size int64) *MultistreamReader {
+	return &MultistreamReader{src: src, ranges: ranges, size: size}
+}
+
+// Read implements io.Reader.
+func (mr *MultistreamReader) Read(p []byte) (int, error) {
+	for len(mr.buf) == 0 {
+		if len(mr.ranges) == 0 {
+			return 0, io.EOF
+		}
+
+		rng := mr.ranges[0]
+		mr.ranges = mr.ranges[1:]
+
+		buf := make([]byte, rng.End-rng.Offset)
+		if _, err := mr.src.ReadAt(buf, rng.Offset); err != nil && err != io.EOF {
+			return 0, fmt.Errorf("unable to read streams at offset %d: %w", rng.Offset, err)
+		}
+
+		// Cut off the final stream of the dump (which is tiny, unlike streams of pages)
+		if rng.End == mr.size {
+			if i := lastStreamStart(buf, 1); i > 0 && len(buf)-i < 1024 {
+				buf = buf[:i]
+			}
+		}
+
+		mr.buf = buf
+	}
+
+	n := copy(p, mr.buf)
+	mr.buf = mr.buf[n:]
+
+	return n, nil
+}
+
+// Close implements io.Closer.
+func (mr *MultistreamReader) Close() error {
+	if c, ok := mr.src.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// HTTPRangeReader reads parts of a remote file using HTTP range requests.
+type HTTPRangeReader struct {
+	Client      *http.Client // Client used for requests
+	URL         string       // URL of the file (possibly with credentials)
+	BearerToken string       // Token sent in the Authorization header (empty for none)
+}
+
+// ReadAt implements io.ReaderAt.
+func (hr *HTTPRangeReader) ReadAt(p []byte, off int64) (int, error) {
+	req, err := NewAuthRequest(http.MethodGet, hr.URL, hr.BearerToken)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1))
+
+	resp, err := hr.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("range request failed: %s", resp.Status)
+	}
+
+	return io.ReadFull(resp.Body, p)
+}
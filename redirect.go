@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+)
+
+// redirectPage holds the fields of a page needed to collect redirects.
+type redirectPage struct {
+	Title     string                      `xml:"title"`    // Title of the page
+	Namespace string                      `xml:"ns"`       // Namespace of the page
+	Redirect  *wordlist.WikipediaRedirect `xml:"redirect"` // Set if the page is a redirect
+}
+
+// CollectRedirects reads a Wikipedia XML dump from r and returns the target of each redirect page in the given
+// namespace by title. Section links of targets (i.e. "John Doe#Life") are dropped.
+func CollectRedirects(r io.Reader, namespace string) (map[string]string, error) {
+	redirects := make(map[string]string)
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return redirects, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to decode XML token: %w", err)
+		}
+
+		t, ok := token.(xml.StartElement)
+		if !ok || t.Name.Local != "page" {
+			continue
+		}
+
+		var p redirectPage
+		if err := decoder.DecodeElement(&p, &t); err != nil {
+			return nil, fmt.Errorf("unable to decode page: %w", err)
+		}
+
+		if p.Redirect != nil && p.Namespace == namespace {
+			target := strings.SplitN(p.Redirect.Title, "#", 2)[0]
+			if target != "" && target != p.Title {
+				redirects[p.Title] = target
+			}
+		}
+	}
+}
+
+// ResolveRedirects follows chains of redirects and returns the number of redirects leading to each final target.
+// Redirects running in a cycle are ignored.
+func ResolveRedirects(redirects map[string]string) map[string]int {
+	counts := make(map[string]int)
+
+	for title := range redirects {
+		target := title
+		seen := map[string]bool{title: true}
+
+		for {
+			next, ok := redirects[target]
+			if !ok {
+				break
+			}
+
+			if seen[next] {
+				target = ""
+				break
+			}
+
+			seen[next] = true
+			target = next
+		}
+
+		if target != "" {
+			counts[target]++
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// GenerateRequest is the JSON body of a request to the "/generate" endpoint of the server.
+type GenerateRequest struct {
+	Language     string `json:"language"`      // Language code of the histogram
+	Count        int    `json:"count"`         // Ignore names with less than this many occurrences
+	Digits       int    `json:"digits"`        // Append up to this many digits
+	SpecialChars string `json:"special-chars"` // Append special characters from this set
+}
+
+// WordlistServer generates wordlists from pre-loaded histograms on request.
+type WordlistServer struct {
+	Histograms map[string][]NameCount // Sorted name frequencies by language code
+	Cases      []string               // Case variants written for each name
+	MaxDigits  int                    // Maximum number of digits a request may ask for
+	MaxSpecial int                    // Maximum number of special characters a request may ask for
+}
+
+// serve is called for the "serve" sub command.
+func serve(cmd *cobra.Command, args []string) {
+	listen, _ := cmd.Flags().GetString("listen")
+	paths, _ := cmd.Flags().GetStringToString("histogram")
+	cases, _ := cmd.Flags().GetStringSlice("case")
+	maxDigits, _ := cmd.Flags().GetInt("max-digits")
+	maxSpecial, _ := cmd.Flags().GetInt("max-special-chars")
+
+	for _, c := range cases {
+		if !wordlist.ValidCase(c) {
+			logrus.Errorf("Unknown case: %s", c)
+			os.Exit(1)
+		}
+	}
+
+	// Load histograms
+	ws := &WordlistServer{
+		Histograms: make(map[string][]NameCount),
+		Cases:      cases,
+		MaxDigits:  maxDigits,
+		MaxSpecial: maxSpecial,
+	}
+
+	for code, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			logrus.Errorf("Unable to open histogram: %v", err)
+			os.Exit(1)
+		}
+
+		hist, err := ReadFrequencies(f)
+		f.Close()
+
+		if err != nil {
+			logrus.Errorf("Unable to read histogram %s: %v", path, err)
+			os.Exit(1)
+		}
+
+		ws.Histograms[code] = SortedHistogram(hist, 1, 0)
+		logrus.Infof("Loaded %d names for language %s", len(hist), code)
+	}
+
+	// Serve
+	mux := http.NewServeMux()
+	mux.HandleFunc("/generate", ws.Generate)
+
+	logrus.Infof("Listening on %s", listen)
+
+	if err := http.ListenAndServe(listen, mux); err != nil {
+		logrus.Errorf("Unable to serve: %v", err)
+		os.Exit(1)
+	}
+}
+
+// Generate handles a request to generate a wordlist, streaming the wordlist as the response body.
+func (ws *WordlistServer) Generate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Decode request (fields not given keep their defaults)
+	req := GenerateRequest{
+		Count:        1,
+		Digits:       4,
+		SpecialChars: wordlist.SpecialCharacters,
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ncs, ok := ws.Histograms[req.Language]
+	if !ok {
+		http.Error(w, fmt.Sprintf("no histogram for language %q (available: %s)", req.Language, ws.languages()),
+			http.StatusNotFound)
+		return
+	}
+
+	if req.Digits < 0 || req.Digits > ws.MaxDigits {
+		http.Error(w, fmt.Sprintf("digits must be between 0 and %d", ws.MaxDigits), http.StatusBadRequest)
+		return
+	}
+
+	if n := utf8.RuneCountInString(req.SpecialChars); n > ws.MaxSpecial {
+		http.Error(w, fmt.Sprintf("at most %d special chars are allowed", ws.MaxSpecial), http.StatusBadRequest)
+		return
+	}
+
+	logrus.Infof("Generating %s wordlist for %s (count %d, digits %d, special chars %q)",
+		req.Language, r.RemoteAddr, req.Count, req.Digits, req.SpecialChars)
+
+	// Stream names (sorted by descending count, so the most frequent ones come first)
+	failed := make(chan struct{})
+
+	opts := &wordlist.OutputOptions{
+		Digits:        req.Digits,
+		SpecialChars:  req.SpecialChars,
+		LineEnding:    "\n",
+		Cases:         ws.Cases,
+		FlushInterval: wordlist.ProgressLines,
+		OnError:       func(error) { close(failed) },
+	}
+
+	ch := make(chan wordlist.Name, 1024)
+	stats := &wordlist.OutputStats{}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	go wordlist.OutputRoutine(w, opts, ch, stats, &wg)
+
+	// Stop early if the client goes away, or writing to it fails
+Names:
+	for _, nc := range ncs {
+		if nc.Count < req.Count {
+			break
+		}
+
+		select {
+		case ch <- wordlist.Name{First: nc.Name}:
+		case <-r.Context().Done():
+			break Names
+		case <-failed:
+			break Names
+		}
+	}
+
+	close(ch)
+	wg.Wait()
+
+	if stats.Err != nil {
+		logrus.Warnf("Unable to write wordlist to %s after %d entries: %v", r.RemoteAddr, stats.Lines, stats.Err)
+		return
+	}
+
+	logrus.Infof("Wrote %d entries for %d names to %s", stats.Lines, stats.Names, r.RemoteAddr)
+}
+
+// languages returns the codes of all loaded histograms.
+func (ws *WordlistServer) languages() string {
+	codes := make([]string, 0, len(ws.Histograms))
+	for code := range ws.Histograms {
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+
+	return strings.Join(codes, ", ")
+}
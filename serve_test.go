@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+)
+
+// failingWriter is a ResponseWriter whose writes fail, like one of a client that went away.
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (fw failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+// newTestServer returns a server with a German histogram of n names.
+func newTestServer(n int) *WordlistServer {
+	ncs := make([]NameCount, n)
+	for i := range ncs {
+		ncs[i] = NameCount{Name: "name" + strconv.Itoa(i), Count: 1}
+	}
+
+	return &WordlistServer{
+		Histograms: map[string][]NameCount{"de": ncs},
+		Cases:      wordlist.DefaultCases,
+		MaxDigits:  4,
+		MaxSpecial: 4,
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ws := newTestServer(2)
+
+	rec := httptest.NewRecorder()
+	ws.Generate(rec, httptest.NewRequest(http.MethodPost, "/generate", strings.NewReader(`{"language": "de", "digits": 0, "special-chars": ""}`)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	want := "name0\nNAME0\nName0\nname1\nNAME1\nName1\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGenerateRejectsLimits(t *testing.T) {
+	ws := newTestServer(2)
+
+	for _, body := range []string{
+		`{"language": "de", "digits": 5}`,
+		`{"language": "de", "digits": -1}`,
+		`{"language": "de", "special-chars": "!$@_%"}`,
+	} {
+		rec := httptest.NewRecorder()
+		ws.Generate(rec, httptest.NewRequest(http.MethodPost, "/generate", strings.NewReader(body)))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGenerateStopsOnWriteError(t *testing.T) {
+	// More names than fit into the channel buffer
+	ws := newTestServer(10000)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		fw := failingWriter{httptest.NewRecorder()}
+		ws.Generate(fw, httptest.NewRequest(http.MethodPost, "/generate", strings.NewReader(`{"language": "de"}`)))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Generate did not return after a write error")
+	}
+}
@@ -0,0 +1,18 @@
+//go:build cgo
+// +build cgo
+
+package main
+
+import (
+	"database/sql"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteSupported is true if this build can read SQLite databases (which requires cgo).
+const SQLiteSupported = true
+
+// OpenSQLite opens the SQLite database at path read-only.
+func OpenSQLite(path string) (*sql.DB, error) {
+	return sql.Open("sqlite3", "file:"+path+"?mode=ro")
+}
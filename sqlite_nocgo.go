@@ -0,0 +1,17 @@
+//go:build !cgo
+// +build !cgo
+
+package main
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// SQLiteSupported is true if this build can read SQLite databases (which requires cgo).
+const SQLiteSupported = false
+
+// OpenSQLite always fails, as the SQLite driver cannot be built without cgo.
+func OpenSQLite(path string) (*sql.DB, error) {
+	return nil, errors.New("SQLite databases can only be read by builds with cgo enabled (CGO_ENABLED=1)")
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Character classes of wordlist entries.
+var CharClasses = []string{"lower", "upper", "mixed", "digits", "alnum", "special"}
+
+// WordlistStats holds statistics about a wordlist.
+type WordlistStats struct {
+	Lines    int            // Total number of lines
+	Unique   int            // Number of unique lines
+	Lengths  map[int]int    // Number of lines per length (in runes)
+	Classes  map[string]int // Number of lines per character class
+	Suffixes map[string]int // Number of lines per suffix
+}
+
+// stats is called for the "stats" sub command.
+func stats(cmd *cobra.Command, args []string) {
+	top, _ := cmd.Flags().GetInt("top")
+
+	// Open wordlist
+	f, err := os.Open(args[0])
+	if err != nil {
+		logrus.Errorf("Unable to open wordlist: %v", err)
+		os.Exit(1)
+	}
+
+	defer f.Close()
+
+	// Collect and print statistics
+	st, err := CollectStats(f)
+	if err != nil {
+		logrus.Errorf("Unable to read wordlist: %v", err)
+		os.Exit(1)
+	}
+
+	st.Print(os.Stdout, top)
+}
+
+// CollectStats reads a wordlist from r and collects its statistics.
+func CollectStats(r io.Reader) (*WordlistStats, error) {
+	st := &WordlistStats{
+		Lengths:  make(map[int]int),
+		Classes:  make(map[string]int),
+		Suffixes: make(map[string]int),
+	}
+
+	seen := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		st.Lines++
+		st.Lengths[utf8.RuneCountInString(line)]++
+		st.Classes[CharClass(line)]++
+
+		if suffix := Suffix(line); suffix != "" {
+			st.Suffixes[suffix]++
+		}
+
+		if _, ok := seen[line]; !ok {
+			seen[line] = struct{}{}
+			st.Unique++
+		}
+	}
+
+	return st, scanner.Err()
+}
+
+// Print writes the statistics in human readable form to w, limiting suffixes to the top most common ones.
+func (st *WordlistStats) Print(w io.Writer, top int) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Lines:\t%d\t\n", st.Lines)
+	fmt.Fprintf(tw, "Unique lines:\t%d\t\n", st.Unique)
+
+	// Length distribution
+	lengths := make([]int, 0, len(st.Lengths))
+	for l := range st.Lengths {
+		lengths = append(lengths, l)
+	}
+
+	sort.Ints(lengths)
+
+	fmt.Fprintf(tw, "\t\t\nLength\tLines\t\n")
+	for _, l := range lengths {
+		fmt.Fprintf(tw, "%d\t%d\t\n", l, st.Lengths[l])
+	}
+
+	// Character classes
+	fmt.Fprintf(tw, "\t\t\nClass\tLines\t\n")
+	for _, c := range CharClasses {
+		fmt.Fprintf(tw, "%s\t%d\t\n", c, st.Classes[c])
+	}
+
+	// Most common suffixes
+	suffixes := SortedHistogram(st.Suffixes, 1, 0)
+	if len(suffixes) > top {
+		suffixes = suffixes[:top]
+	}
+
+	fmt.Fprintf(tw, "\t\t\nSuffix\tLines\t\n")
+	for _, s := range suffixes {
+		fmt.Fprintf(tw, "%s\t%d\t\n", s.Name, s.Count)
+	}
+
+	tw.Flush()
+}
+
+// CharClass returns the character class of s: "lower" or "upper" for letters of a single case, "mixed" for
+// letters of both cases, "digits" for digits only, "alnum" for letters and digits, and "special" for
+// anything else.
+func CharClass(s string) string {
+	var lower, upper, digit, other bool
+
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	letter := lower || upper
+
+	switch {
+	case other || s == "":
+		return "special"
+	case letter && digit:
+		return "alnum"
+	case digit:
+		return "digits"
+	case lower && upper:
+		return "mixed"
+	case upper:
+		return "upper"
+	default:
+		return "lower"
+	}
+}
+
+// Suffix returns the trailing non-letter part of s (i.e. "123!" for "anna123!").
+func Suffix(s string) string {
+	i := len(s)
+	for i > 0 {
+		r, size := utf8.DecodeLastRuneInString(s[:i])
+		if unicode.IsLetter(r) {
+			break
+		}
+
+		i -= size
+	}
+
+	return s[i:]
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Latest release of names-wordlist.
+const (
+	LatestReleaseURL    = "https://api.github.com/repos/crissyfield/names-wordlist/releases/latest"
+	VersionCheckTimeout = 3 * time.Second
+)
+
+// checkVersion logs a message if a release newer than current is available. It fails silently (only logging at
+// debug level), i.e. if the network is unavailable.
+func checkVersion(current string) {
+	client := &http.Client{Timeout: VersionCheckTimeout}
+
+	resp, err := client.Get(LatestReleaseURL)
+	if err != nil {
+		logrus.Debugf("Unable to check for a newer version: %v", err)
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logrus.Debugf("Unable to check for a newer version: %s", resp.Status)
+		return
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"` // Tag of the release (i.e. "v1.1.0")
+		URL     string `json:"html_url"` // Release page
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		logrus.Debugf("Unable to check for a newer version: %v", err)
+		return
+	}
+
+	newer, err := NewerVersion(release.TagName, current)
+	if err != nil {
+		logrus.Debugf("Unable to check for a newer version: %v", err)
+		return
+	}
+
+	if newer {
+		logrus.Warnf("A newer version of names-wordlist is available: %s (running %s), see %s",
+			strings.TrimPrefix(release.TagName, "v"), current, release.URL)
+	} else {
+		logrus.Debugf("Running the latest version of names-wordlist (%s)", current)
+	}
+}
+
+// NewerVersion returns true if version latest (i.e. "v1.2.0") is newer than current (i.e. "1.0.0"). Versions
+// are compared by their numeric parts, a missing part counting as 0.
+func NewerVersion(latest string, current string) (bool, error) {
+	l, err := parseVersion(latest)
+	if err != nil {
+		return false, err
+	}
+
+	c, err := parseVersion(current)
+	if err != nil {
+		return false, err
+	}
+
+	for i := 0; i < len(l) || i < len(c); i++ {
+		var lp, cp int
+		if i < len(l) {
+			lp = l[i]
+		}
+
+		if i < len(c) {
+			cp = c[i]
+		}
+
+		if lp != cp {
+			return lp > cp, nil
+		}
+	}
+
+	return false, nil
+}
+
+// parseVersion returns the numeric parts of a version like "v1.2.3" (ignoring any pre-release or build suffix).
+func parseVersion(v string) ([]int, error) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	var parts []int
+
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version: %s", v)
+		}
+
+		parts = append(parts, n)
+	}
+
+	return parts, nil
+}
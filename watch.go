@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// WatchSettle is the time a watched dump file must remain unchanged before the wordlist is regenerated, so
+// that a dump still being written (or copied) is not read.
+const WatchSettle = 10 * time.Second
+
+// watchDump generates the wordlist from the dump file at path and regenerates it whenever the file changes.
+// Each run is a child process with the same arguments (but without --watch), so that a failing run doesn't
+// end watching. It never returns.
+func watchDump(path string) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logrus.Errorf("Unable to watch dump file: %v", err)
+		os.Exit(1)
+	}
+
+	// Watch the directory, as refreshed dumps are often moved in place of the old one
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		logrus.Errorf("Unable to watch dump file: %v", err)
+		os.Exit(1)
+	}
+
+	if err := watcher.Add(filepath.Dir(abs)); err != nil {
+		logrus.Errorf("Unable to watch dump file: %v", err)
+		os.Exit(1)
+	}
+
+	generate := func() {
+		logrus.Infof("Generating wordlist from %s", path)
+
+		if err := runWithoutWatch(); err != nil {
+			logrus.Errorf("Unable to generate wordlist: %v", err)
+		} else {
+			logrus.Infof("Generated wordlist, watching %s for changes", path)
+		}
+	}
+
+	generate()
+
+	// Regenerate once the file settled after changes
+	settle := time.NewTimer(WatchSettle)
+	settle.Stop()
+
+	for {
+		select {
+		case ev := <-watcher.Events:
+			if filepath.Clean(ev.Name) != abs || ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+
+			logrus.Debugf("Dump file changed: %s", ev)
+			settle.Reset(WatchSettle)
+
+		case err := <-watcher.Errors:
+			logrus.Warnf("Error watching dump file: %v", err)
+
+		case <-settle.C:
+			if _, err := os.Stat(abs); err != nil {
+				logrus.Warnf("Dump file is gone, waiting for it to reappear: %v", err)
+				continue
+			}
+
+			generate()
+		}
+	}
+}
+
+// runWithoutWatch runs this executable with the arguments it was called with, except for --watch.
+func runWithoutWatch() error {
+	var args []string
+	for _, arg := range os.Args[1:] {
+		if arg != "--watch" && !strings.HasPrefix(arg, "--watch=") {
+			args = append(args, arg)
+		}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(exe, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	// Also override watch set in the config file or environment
+	cmd.Env = append(os.Environ(), "NAMES_WORDLIST_WATCH=false")
+
+	return cmd.Run()
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crissyfield/names-wordlist/wordlist"
+)
+
+// WikidataQuery selects the labels of all given names (Q202444 or subclasses) in the given language, together
+// with the number of humans (Q5) carrying them.
+const WikidataQuery = `SELECT ?name (COUNT(?person) AS ?count) WHERE {
+  ?person wdt:P31 wd:Q5; wdt:P735 ?given.
+  ?given wdt:P31/wdt:P279* wd:Q202444; rdfs:label ?name.
+  FILTER(LANG(?name) = "%s")
+}
+GROUP BY ?name
+ORDER BY DESC(?count) ?name
+LIMIT %d
+OFFSET %d`
+
+// WikidataParser fetches given names and their frequency from a Wikidata SPARQL endpoint.
+type WikidataParser struct {
+	Endpoint  string             // URL of the SPARQL endpoint
+	Language  string             // Language of the name labels
+	PageSize  int                // Number of names fetched per request
+	Stopwords wordlist.Stopwords // Names that are skipped
+
+	Transforms wordlist.NameTransforms // Applied to names before splitting them (nil to disable)
+
+	Pages int // Number of result pages fetched so far
+	Names int // Number of first names extracted so far
+}
+
+// wikidataResult is the JSON response of a SPARQL endpoint.
+type wikidataResult struct {
+	Results struct {
+		Bindings []struct {
+			Name  struct{ Value string } `json:"name"`
+			Count struct{ Value string } `json:"count"`
+		} `json:"bindings"`
+	} `json:"results"`
+}
+
+// Parse fetches all given names page by page and calls emit for each of them as often as they occur.
+func (wp *WikidataParser) Parse(client *http.Client, emit func(wordlist.Name)) error {
+	if wp.PageSize < 1 {
+		return fmt.Errorf("invalid page size: %d", wp.PageSize)
+	}
+
+	for offset := 0; ; offset += wp.PageSize {
+		res, err := wp.fetch(client, offset)
+		if err != nil {
+			return err
+		}
+
+		wp.Pages++
+
+		for _, b := range res.Results.Bindings {
+			count, err := strconv.Atoi(b.Count.Value)
+			if err != nil {
+				return fmt.Errorf("invalid count for %s: %w", b.Name.Value, err)
+			}
+
+			firstname := wordlist.PickFirstname(strings.TrimSpace(wp.Transforms.Apply(b.Name.Value)), wp.Stopwords, false)
+			if firstname == "" {
+				continue
+			}
+
+			for i := 0; i < count; i++ {
+				wp.Names++
+				emit(wordlist.Name{First: firstname})
+			}
+		}
+
+		// Last page
+		if len(res.Results.Bindings) < wp.PageSize {
+			return nil
+		}
+	}
+}
+
+// fetch runs the query for a single page of results starting at offset.
+func (wp *WikidataParser) fetch(client *http.Client, offset int) (*wikidataResult, error) {
+	query := fmt.Sprintf(WikidataQuery, wp.Language, wp.PageSize, offset)
+
+	req, err := http.NewRequest("GET", wp.Endpoint+"?query="+url.QueryEscape(query), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/sparql-results+json")
+	req.Header.Set("User-Agent", "names-wordlist (https://github.com/crissyfield/names-wordlist)")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query endpoint: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	var res wikidataResult
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("unable to decode results: %w", err)
+	}
+
+	return &res, nil
+}
+
+// WikidataGivenNameClasses are the classes of given name items in Wikidata (given name, male, female, and
+// unisex given name).
+var WikidataGivenNameClasses = map[string]bool{
+	"Q202444":   true,
+	"Q12308941": true,
+	"Q11879590": true,
+	"Q3409032":  true,
+}
+
+// WikidataDumpParser extracts given names and their frequency from a Wikidata JSON dump.
+type WikidataDumpParser struct {
+	Language  string             // Language of the name labels
+	Stopwords wordlist.Stopwords // Names that are skipped
+
+	Transforms wordlist.NameTransforms // Applied to names before splitting them (nil to disable)
+
+	Entities int // Number of entities read so far
+	Humans   int // Number of humans with a given name read so far
+	Names    int // Number of first names extracted so far
+}
+
+// wikidataEntity holds the fields of a Wikidata entity needed to extract given names.
+type wikidataEntity struct {
+	ID     string `json:"id"` // Item ID (i.e. "Q42")
+	Labels map[string]struct {
+		Value string `json:"value"`
+	} `json:"labels"` // Labels by language
+	Claims struct {
+		InstanceOf []wikidataClaim `json:"P31"`  // Classes of the item
+		GivenName  []wikidataClaim `json:"P735"` // Given names of a human
+	} `json:"claims"`
+}
+
+// wikidataClaim is a single statement about an entity referring to another item.
+type wikidataClaim struct {
+	Mainsnak struct {
+		Datavalue struct {
+			Value struct {
+				ID string `json:"id"`
+			} `json:"value"`
+		} `json:"datavalue"`
+	} `json:"mainsnak"`
+}
+
+// Parse reads a Wikidata JSON dump (a single array holding all entities) from r entity by entity. It counts
+// the given names of all humans (instance of Q5) and calls emit for the label of each given name as often as
+// it occurs, once the whole dump has been read (as given name items may appear after the humans using them).
+func (wp *WikidataDumpParser) Parse(r io.Reader, emit func(wordlist.Name)) error {
+	counts := make(map[string]int)
+	labels := make(map[string]string)
+
+	decoder := json.NewDecoder(r)
+
+	if t, err := decoder.Token(); err != nil {
+		return fmt.Errorf("unable to read dump: %w", err)
+	} else if d, ok := t.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("dump is not a JSON array")
+	}
+
+	for decoder.More() {
+		var e wikidataEntity
+		if err := decoder.Decode(&e); err != nil {
+			return fmt.Errorf("unable to decode entity %d: %w", wp.Entities+1, err)
+		}
+
+		wp.Entities++
+
+		human, givenName := false, false
+		for _, c := range e.Claims.InstanceOf {
+			id := c.Mainsnak.Datavalue.Value.ID
+			human = human || id == "Q5"
+			givenName = givenName || WikidataGivenNameClasses[id]
+		}
+
+		// Count given names of humans
+		if human && len(e.Claims.GivenName) > 0 {
+			wp.Humans++
+
+			for _, c := range e.Claims.GivenName {
+				if id := c.Mainsnak.Datavalue.Value.ID; id != "" {
+					counts[id]++
+				}
+			}
+		}
+
+		// Remember labels of given names
+		if givenName {
+			if l, ok := e.Labels[wp.Language]; ok {
+				labels[e.ID] = l.Value
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		count := counts[id]
+
+		firstname := wordlist.PickFirstname(strings.TrimSpace(wp.Transforms.Apply(labels[id])), wp.Stopwords, false)
+		if firstname == "" {
+			continue
+		}
+
+		for i := 0; i < count; i++ {
+			wp.Names++
+			emit(wordlist.Name{First: firstname})
+		}
+	}
+
+	return nil
+}
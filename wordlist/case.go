@@ -0,0 +1,86 @@
+package wordlist
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Cases holds all known case variants.
+var Cases = []string{"lower", "upper", "title", "capitalize"}
+
+// DefaultCases holds the case variants written by default.
+var DefaultCases = []string{"lower", "upper", "title"}
+
+// Title modes, i.e. how the title case variant capitalizes names of multiple words.
+const (
+	TitleWord   = "word"   // Capitalize each word ("Anna Maria")
+	TitleSingle = "single" // Capitalize only the first letter of the whole name ("Anna maria")
+)
+
+// ValidTitleMode returns true if m is a known title mode.
+func ValidTitleMode(m string) bool {
+	return m == TitleWord || m == TitleSingle
+}
+
+// ValidCase returns true if c is a known case variant.
+func ValidCase(c string) bool {
+	for _, k := range Cases {
+		if c == k {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ApplyCase returns s converted to the given case variant.
+func ApplyCase(s string, c string) string {
+	switch c {
+	case "lower":
+		return strings.ToLower(s)
+	case "upper":
+		return strings.ToUpper(s)
+	case "title":
+		return cases.Title(language.Und).String(s)
+	case "capitalize":
+		return Capitalize(s)
+	default:
+		return s
+	}
+}
+
+// Capitalize returns s with its first rune in upper case and all others in lower case (i.e. "Mary" for "mARY",
+// or "Anna maria" for "ANNA MARIA"), unlike title case capitalizing each word.
+func Capitalize(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	if n == 0 {
+		return s
+	}
+
+	return string(unicode.ToUpper(r)) + strings.ToLower(s[n:])
+}
+
+// CaseVariants returns base converted to each of the given case variants. If preserve is set, base is
+// returned in its original casing first (i.e. "McDonald"), unless it equals one of the other variants.
+func CaseVariants(base string, cases []string, preserve bool) []string {
+	words := make([]string, 0, len(cases)+1)
+	for _, c := range cases {
+		words = append(words, ApplyCase(base, c))
+	}
+
+	if preserve {
+		for _, w := range words {
+			if w == base {
+				return words
+			}
+		}
+
+		words = append([]string{base}, words...)
+	}
+
+	return words
+}
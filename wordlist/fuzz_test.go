@@ -0,0 +1,54 @@
+package wordlist
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzExtractNames feeds arbitrary page texts through template matching, field parsing, and name splitting of
+// each built-in language. Run with "go test -fuzz FuzzExtractNames".
+func FuzzExtractNames(f *testing.F) {
+	f.Add("{{Personendaten\n|NAME=Doe, John\n|GEBURTSDATUM=3. März 1975\n}}")
+	f.Add("{{Personendaten\n|NAME=Doe, John {{Anker|JD}}\n}}")
+	f.Add("{{Personendaten|NAME=[[John Doe|Doe, John]]|NAME=Roe, <!-- Kommentar -->Jane}}")
+	f.Add("{{Persondata|NAME=Łukasiewicz, Żaneta}}{{Osoba|jméno=Novák, Jan}}")
+	f.Add("{{Infobox person\n| name = Mary Ann Smith\n| birth_date = {{birth date|1970|5|17}}\n}}")
+	f.Add("{{Infobox person| name = JohnDoe}}[[Category:1970 births]]")
+	f.Add("{{Personendaten|NAME=,,,|NAME=Doe,|NAME=, John}}")
+	f.Add("{{{{Personendaten|NAME=" + strings.Repeat("{{[[|", 100) + "}}")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		const title = "John Doe"
+
+		for _, code := range LanguageCodes() {
+			dp := &DumpParser{
+				Language:      Languages[code],
+				Stopwords:     NewStopwords("von", "van"),
+				Combine:       true,
+				CamelCase:     true,
+				TitleFallback: true,
+				BirthYears:    true,
+				Words:         -1,
+			}
+
+			dp.parseText(title, text, func(n Name) {
+				if n.First == "" {
+					t.Fatalf("%s: empty first name", code)
+				}
+
+				if strings.ContainsAny(n.First, " \t\n") {
+					t.Fatalf("%s: first name %q not split", code, n.First)
+				}
+
+				if len(n.First)+len(n.Last) > len(text)+len(title) {
+					t.Fatalf("%s: name %q %q longer than its page", code, n.First, n.Last)
+				}
+
+				if utf8.ValidString(text) && (!utf8.ValidString(n.First) || !utf8.ValidString(n.Last)) {
+					t.Fatalf("%s: invalid UTF-8 in name %q %q", code, n.First, n.Last)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,134 @@
+// Package wordlist extracts first names from the person data of Wikipedia dumps and expands them into wordlist
+// entries, as done by the names-wordlist command.
+package wordlist
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Options controls Generate.
+type Options struct {
+	Language  string         // Language code of the dump (i.e. "de")
+	Count     int            // Ignore names with less than this many occurrences (0 for 1)
+	Combine   bool           // Also generate first names combined with last names
+	Stopwords Stopwords      // Tokens that are skipped when picking first names (nil for DefaultStopwords)
+	Output    *OutputOptions // Expansion of names into entries (nil for NewOutputOptions, line ending and name files are ignored)
+}
+
+// NewOutputOptions returns the output options of the names-wordlist command with all flags at their defaults.
+func NewOutputOptions() *OutputOptions {
+	return &OutputOptions{
+		Digits:            4,
+		SpecialChars:      SpecialCharacters,
+		CombineSeparators: []string{"", ".", "_", "-"},
+		TokenSeparators:   []string{"", "_", "-"},
+		LineEnding:        "\n",
+		Cases:             DefaultCases,
+	}
+}
+
+// Generate reads a Wikipedia XML dump from r and returns a channel receiving the wordlist entries of each
+// first name that reached the given count, just like the names-wordlist command writes them without flags.
+// The whole dump is read before returning, so that errors parsing it are returned instead. Entries are
+// generated while the channel is read, and it's closed after the last one; it must be read until then.
+func Generate(r io.Reader, opts Options) (<-chan string, error) {
+	lang, ok := Languages[opts.Language]
+	if !ok {
+		return nil, fmt.Errorf("unknown language: %s", opts.Language)
+	}
+
+	count := opts.Count
+	if count <= 0 {
+		count = 1
+	}
+
+	stopwords := opts.Stopwords
+	if stopwords == nil {
+		stopwords = NewStopwords(DefaultStopwords...)
+	}
+
+	// Keep names in the order they reached the count
+	var names []Name
+
+	firstnames := make(map[string]int)
+	combined := make(map[string]int)
+
+	dp := &DumpParser{
+		Language:  lang,
+		Stopwords: stopwords,
+		Combine:   opts.Combine,
+		Namespace: "0",
+	}
+
+	err := dp.Parse(r, func(n Name) {
+		hist, key := firstnames, n.First
+		if n.Last != "" {
+			hist, key = combined, n.First+" "+n.Last
+		}
+
+		hist[key]++
+		if hist[key] == count {
+			names = append(names, n)
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy output options, as entries are split at line endings and never written to name files
+	out := NewOutputOptions()
+	if opts.Output != nil {
+		*out = *opts.Output
+	}
+
+	out.LineEnding = "\n"
+	out.NameFile = nil
+
+	ch := make(chan Name, len(names))
+	for _, n := range names {
+		ch <- n
+	}
+
+	close(ch)
+
+	entries := make(chan string, 1024)
+
+	go func() {
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		OutputRoutine(&entryWriter{entries: entries}, out, ch, &OutputStats{}, &wg)
+		close(entries)
+	}()
+
+	return entries, nil
+}
+
+// entryWriter sends each line written to it to a channel, without its line ending.
+type entryWriter struct {
+	entries chan<- string // Receives the lines
+	partial string        // Start of a line not terminated yet
+}
+
+// Write implements io.Writer.
+func (ew *entryWriter) Write(p []byte) (int, error) {
+	s := ew.partial + string(p)
+
+	for {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			break
+		}
+
+		ew.entries <- s[:i]
+		s = s[i+1:]
+	}
+
+	ew.partial = s
+
+	return len(p), nil
+}
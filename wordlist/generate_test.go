@@ -0,0 +1,86 @@
+package wordlist
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const generateDump = `<mediawiki>
+<page><title>John Doe</title><ns>0</ns><id>1</id><revision><id>10</id><text>{{Personendaten|NAME=Doe, John}}</text></revision></page>
+<page><title>John Roe</title><ns>0</ns><id>2</id><revision><id>11</id><text>{{Personendaten|NAME=Roe, John}}</text></revision></page>
+<page><title>Jane Doe</title><ns>0</ns><id>3</id><revision><id>12</id><text>{{Personendaten|NAME=Doe, Jane}}</text></revision></page>
+</mediawiki>`
+
+// collect returns all entries received from ch.
+func collect(ch <-chan string) []string {
+	var entries []string
+	for entry := range ch {
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func TestGenerate(t *testing.T) {
+	opts := Options{
+		Language: "de",
+		Count:    2,
+		Output:   &OutputOptions{Cases: []string{"lower"}, Digits: 1},
+	}
+
+	ch, err := Generate(strings.NewReader(generateDump), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"john", "john0", "john1", "john2", "john3", "john4", "john5", "john6", "john7", "john8", "john9"}
+	if got := collect(ch); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGenerateDefaults(t *testing.T) {
+	ch, err := Generate(strings.NewReader(generateDump), Options{Language: "de", Combine: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := make(map[string]bool)
+	for _, entry := range collect(ch) {
+		entries[entry] = true
+	}
+
+	for _, entry := range []string{"john", "Jane12!", "j.doe", "JOHN_ROE1", "jane1975"} {
+		if !entries[entry] {
+			t.Errorf("entry %q missing", entry)
+		}
+	}
+
+	if entries["doe"] || entries[""] {
+		t.Error("unexpected entries")
+	}
+}
+
+func TestGenerateLineEnding(t *testing.T) {
+	opts := Options{
+		Language: "de",
+		Output:   &OutputOptions{Cases: []string{"lower"}, NamesOnly: true, LineEnding: "\r\n", NameSuffix: "."},
+	}
+
+	ch, err := Generate(strings.NewReader(generateDump), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Entries are received without line ending
+	if got, want := collect(ch), []string{"john.", "jane."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGenerateUnknownLanguage(t *testing.T) {
+	if _, err := Generate(strings.NewReader(generateDump), Options{Language: "xx"}); err == nil {
+		t.Error("no error for unknown language")
+	}
+}
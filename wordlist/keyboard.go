@@ -0,0 +1,52 @@
+package wordlist
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// ShortDigitSuffixes is the number of digit suffixes with up to two digits at the start of DigitCombinations:
+// the empty suffix, 10 with one digit, and 100 with two digits.
+const ShortDigitSuffixes = 1 + 10 + 100
+
+// DefaultKeyboardWalks holds the keyboard walks appended to names if no walk file is given.
+var DefaultKeyboardWalks = []string{"1qaz", "2wsx", "qwerty", "asdf1234"}
+
+// LoadKeyboardWalks reads keyboard walks from r, one per line. Empty lines and lines starting with '#' are
+// skipped.
+func LoadKeyboardWalks(r io.Reader) ([]string, error) {
+	var walks []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		walks = append(walks, line)
+	}
+
+	return walks, scanner.Err()
+}
+
+// SuffixCombinations returns the digit suffixes for the given number of digits together with the keyboard
+// walks. Walks are placed after the suffixes of up to two digits, but before years and longer numbers.
+func SuffixCombinations(digits int, walks []string) []string {
+	combs := DigitCombinations(digits)
+	if len(walks) == 0 {
+		return combs
+	}
+
+	i := ShortDigitSuffixes
+	if i > len(combs) {
+		i = len(combs)
+	}
+
+	suffixes := make([]string, 0, len(combs)+len(walks))
+	suffixes = append(suffixes, combs[:i]...)
+	suffixes = append(suffixes, walks...)
+
+	return append(suffixes, combs[i:]...)
+}
@@ -0,0 +1,268 @@
+package wordlist
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	AbstractIndexDE = "https://dumps.wikimedia.org/dewiki/latest/dewiki-latest-pages-articles.xml.bz2"
+	AbstractIndexPL = "https://dumps.wikimedia.org/plwiki/latest/plwiki-latest-pages-articles.xml.bz2"
+	AbstractIndexCS = "https://dumps.wikimedia.org/cswiki/latest/cswiki-latest-pages-articles.xml.bz2"
+	AbstractIndexEN = "https://dumps.wikimedia.org/enwiki/latest/enwiki-latest-pages-articles.xml.bz2"
+)
+
+// TemplateExtractor extracts the raw name values from the text of a single page.
+type TemplateExtractor interface {
+	Match(text string) []string
+	Templates(text string) []string                 // Raw templates matched in text, before parsing their fields
+	TemplateFields(template string) []TemplateField // Fields of a raw template as returned by Templates
+}
+
+// TemplateField is a single key/value field of a template.
+type TemplateField struct {
+	Key   string // Name of the field (as written)
+	Value string // Value of the field
+}
+
+// RegexpExtractor is a TemplateExtractor that matches person data templates using a regular expression and
+// returns the values of the given fields.
+type RegexpExtractor struct {
+	Template *regexp.Regexp // Matches person data templates, capturing their fields
+	Fields   []string       // Template fields holding the name (lower case)
+}
+
+// Match returns the values of all name fields in all person data templates of text.
+func (re *RegexpExtractor) Match(text string) []string {
+	var values []string
+
+	// Iterate through all {{Persondata}} templates
+	templates := re.Template.FindAllStringSubmatch(StripNestedTemplates(text), -1)
+	for _, tmpl := range templates {
+		for _, field := range ParseTemplateFields(tmpl[1]) {
+			key := strings.ToLower(field.Key)
+			for _, f := range re.Fields {
+				if key == f {
+					values = append(values, field.Value)
+					break
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+// TemplateFields returns the fields of a template as returned by Templates.
+func (re *RegexpExtractor) TemplateFields(template string) []TemplateField {
+	tmpl := re.Template.FindStringSubmatch(template)
+	if tmpl == nil {
+		return nil
+	}
+
+	return ParseTemplateFields(tmpl[1])
+}
+
+// ParseTemplateFields splits the fields of a template (without its name and braces) into key/value pairs,
+// skipping parts that are no fields.
+func ParseTemplateFields(s string) []TemplateField {
+	var fields []TemplateField
+
+	for _, sub := range strings.Split(StripWikiMarkup(s), "|") {
+		// Parse key/value of field
+		kv := TemplateFieldsRegExp.FindStringSubmatch(sub)
+		if kv == nil {
+			continue
+		}
+
+		fields = append(fields, TemplateField{Key: kv[1], Value: strings.TrimSpace(kv[2])})
+	}
+
+	return fields
+}
+
+// Templates returns all person data templates of text as matched (without nested templates and comments),
+// before parsing their fields.
+func (re *RegexpExtractor) Templates(text string) []string {
+	return re.Template.FindAllString(StripNestedTemplates(text), -1)
+}
+
+// Name orders of template values.
+const (
+	NameOrderLastFirst = "lastfirst" // "Lastname, Firstname"
+	NameOrderFirstLast = "firstlast" // "Firstname Lastname"
+)
+
+// Language describes how person data is extracted from the Wikipedia dump of a single language.
+type Language struct {
+	DumpURL        string            // URL of the latest dump
+	Extractor      TemplateExtractor // Extracts names from page texts
+	NameOrder      string            // Order of names in template values (empty for NameOrderLastFirst)
+	NameSeparator  *regexp.Regexp    // Separates names in template values (nil for the default of the order)
+	PersonCategory *regexp.Regexp    // Matches categories of articles about persons (nil if unknown)
+	BirthDate      TemplateExtractor // Extracts birth dates from page texts (nil if unknown)
+}
+
+// SplitName splits a template value into first and last name according to the name order of the language.
+// It returns false if the value holds only a single name.
+func (l *Language) SplitName(value string) (string, string, bool) {
+	sep := l.NameSeparator
+
+	if l.NameOrder == NameOrderFirstLast {
+		if sep == nil {
+			sep = FirstLastSeparatorRegExp
+		}
+
+		// First names are everything but the last part
+		name := sep.Split(strings.TrimSpace(value), -1)
+		if len(name) < 2 {
+			return "", "", false
+		}
+
+		return strings.Join(name[:len(name)-1], " "), name[len(name)-1], true
+	}
+
+	if sep == nil {
+		sep = NameSeperatorRegExp
+	}
+
+	name := sep.Split(value, -1)
+	if len(name) < 2 {
+		return "", "", false
+	}
+
+	return name[1], name[0], true
+}
+
+// ValidNameOrder returns true if order is a known name order.
+func ValidNameOrder(order string) bool {
+	return order == NameOrderLastFirst || order == NameOrderFirstLast
+}
+
+// Languages holds all known languages by their code.
+var Languages = map[string]*Language{
+	"de": {
+		DumpURL:        AbstractIndexDE,
+		Extractor:      &RegexpExtractor{Template: PersonDataTemplateRegExpDE, Fields: []string{"name"}},
+		PersonCategory: PersonCategoryRegExpDE,
+		BirthDate:      &RegexpExtractor{Template: PersonDataTemplateRegExpDE, Fields: []string{"geburtsdatum"}},
+	},
+	"pl": {
+		DumpURL:        AbstractIndexPL,
+		Extractor:      &RegexpExtractor{Template: PersonDataTemplateRegExpPL, Fields: []string{"name"}},
+		PersonCategory: PersonCategoryRegExpPL,
+	},
+	"cs": {
+		DumpURL:        AbstractIndexCS,
+		Extractor:      &RegexpExtractor{Template: PersonDataTemplateRegExpCS, Fields: []string{"jméno"}},
+		PersonCategory: PersonCategoryRegExpCS,
+	},
+	"en": {
+		// The English Wikipedia has no person data templates anymore, but biographical infoboxes with the name
+		// as "Firstname Lastname"
+		DumpURL:        AbstractIndexEN,
+		Extractor:      &RegexpExtractor{Template: InfoboxPersonRegExpEN, Fields: []string{"name"}},
+		NameOrder:      NameOrderFirstLast,
+		PersonCategory: PersonCategoryRegExpEN,
+	},
+}
+
+// LanguageConfig is the definition of a single language in a languages file.
+type LanguageConfig struct {
+	DumpURL  string   `yaml:"dump-url"` // URL of the latest dump
+	Template string   `yaml:"template"` // Regular expression matching templates, capturing their fields
+	Fields   []string `yaml:"fields"`   // Template fields holding the name
+
+	NameOrder     string `yaml:"name-order"`     // Order of names: "lastfirst" (default) or "firstlast"
+	NameSeparator string `yaml:"name-separator"` // Regular expression separating names
+
+	PersonCategory  string   `yaml:"person-category"`   // Regular expression matching categories of persons
+	BirthDateFields []string `yaml:"birth-date-fields"` // Template fields holding the birth date
+}
+
+// LoadLanguages reads language definitions in YAML format from r and adds them to Languages, replacing
+// built-in languages with the same code.
+func LoadLanguages(r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var configs map[string]LanguageConfig
+	if err := yaml.UnmarshalStrict(data, &configs); err != nil {
+		return err
+	}
+
+	for code, cfg := range configs {
+		tmpl, err := regexp.Compile(cfg.Template)
+		if err != nil {
+			return fmt.Errorf("invalid template for language %s: %w", code, err)
+		}
+
+		if tmpl.NumSubexp() < 1 {
+			return fmt.Errorf("template for language %s does not capture its fields", code)
+		}
+
+		if len(cfg.Fields) == 0 {
+			return fmt.Errorf("no fields given for language %s", code)
+		}
+
+		fields := make([]string, len(cfg.Fields))
+		for i, f := range cfg.Fields {
+			fields[i] = strings.ToLower(f)
+		}
+
+		lang := &Language{
+			DumpURL:   cfg.DumpURL,
+			Extractor: &RegexpExtractor{Template: tmpl, Fields: fields},
+			NameOrder: cfg.NameOrder,
+		}
+
+		if cfg.NameOrder != "" && !ValidNameOrder(cfg.NameOrder) {
+			return fmt.Errorf("invalid name order for language %s: %s", code, cfg.NameOrder)
+		}
+
+		if cfg.NameSeparator != "" {
+			if lang.NameSeparator, err = regexp.Compile(cfg.NameSeparator); err != nil {
+				return fmt.Errorf("invalid name separator for language %s: %w", code, err)
+			}
+		}
+
+		if cfg.PersonCategory != "" {
+			if lang.PersonCategory, err = regexp.Compile(cfg.PersonCategory); err != nil {
+				return fmt.Errorf("invalid person category for language %s: %w", code, err)
+			}
+		}
+
+		if len(cfg.BirthDateFields) > 0 {
+			dateFields := make([]string, len(cfg.BirthDateFields))
+			for i, f := range cfg.BirthDateFields {
+				dateFields[i] = strings.ToLower(f)
+			}
+
+			lang.BirthDate = &RegexpExtractor{Template: tmpl, Fields: dateFields}
+		}
+
+		Languages[code] = lang
+	}
+
+	return nil
+}
+
+// LanguageCodes returns the sorted codes of all known languages.
+func LanguageCodes() []string {
+	codes := make([]string, 0, len(Languages))
+	for code := range Languages {
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+
+	return codes
+}
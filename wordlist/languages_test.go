@@ -0,0 +1,197 @@
+package wordlist
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// extractorFR matches the French person data template as defined in etc/languages.yaml.
+var extractorFR = &RegexpExtractor{
+	Template: regexp.MustCompile(`(?i:\{\{métadonnées personne([^\}]+)\}\})`),
+	Fields:   []string{"nom"},
+}
+
+func TestExtractorMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		extractor TemplateExtractor
+		text      string
+		want      []string
+	}{
+		{
+			name:      "de",
+			extractor: Languages["de"].Extractor,
+			text:      "{{Personendaten\n|NAME=Doe, John\n|ALTERNATIVNAMEN=\n|KURZBESCHREIBUNG=Testperson\n}}",
+			want:      []string{"Doe, John"},
+		},
+		{
+			name:      "de unicode",
+			extractor: Languages["de"].Extractor,
+			text:      "{{Personendaten\n|NAME=Łukasiewicz, Żaneta Ærø\n}}",
+			want:      []string{"Łukasiewicz, Żaneta Ærø"},
+		},
+		{
+			name:      "de empty field",
+			extractor: Languages["de"].Extractor,
+			text:      "{{Personendaten\n|NAME=\n|KURZBESCHREIBUNG=Testperson\n}}",
+			want:      nil,
+		},
+		{
+			name:      "de piped link",
+			extractor: Languages["de"].Extractor,
+			text:      "{{Personendaten\n|NAME=[[John Doe (Schauspieler)|Doe, John]]\n}}",
+			want:      []string{"Doe, John"},
+		},
+		{
+			name:      "de nested template after name",
+			extractor: Languages["de"].Extractor,
+			text:      "{{Personendaten\n|NAME=Doe, John\n|GEBURTSDATUM={{Datum|1970|1|1}}\n}}",
+			want:      []string{"Doe, John"},
+		},
+		{
+			name:      "de nested template in name",
+			extractor: Languages["de"].Extractor,
+			text:      "{{Personendaten\n|NAME=Doe, John {{Anker|JD}}\n}}",
+			want:      []string{"Doe, John"},
+		},
+		{
+			name:      "de deeply nested template in name",
+			extractor: Languages["de"].Extractor,
+			text:      "{{Personendaten\n|NAME=Doe, John{{Anker|{{lang|de|JD}}}}\n|GEBURTSORT=Berlin\n}}",
+			want:      []string{"Doe, John"},
+		},
+		{
+			name:      "de comment",
+			extractor: Languages["de"].Extractor,
+			text:      "{{Personendaten\n|NAME=Doe, <!-- Kommentar -->John\n}}",
+			want:      []string{"Doe, John"},
+		},
+		{
+			name:      "de comment with braces",
+			extractor: Languages["de"].Extractor,
+			text:      "{{Personendaten\n|NAME=Doe, John<!-- }} -->\n}}",
+			want:      []string{"Doe, John"},
+		},
+		{
+			name:      "de multiple templates",
+			extractor: Languages["de"].Extractor,
+			text:      "{{Personendaten\n|NAME=Doe, John\n}}\n{{personendaten|NAME=Roe, Jane}}",
+			want:      []string{"Doe, John", "Roe, Jane"},
+		},
+		{
+			name:      "fr",
+			extractor: extractorFR,
+			text:      "{{Métadonnées personne\n|NOM=Dupont, Jean-François\n|DATE DE NAISSANCE=1970\n}}",
+			want:      []string{"Dupont, Jean-François"},
+		},
+		{
+			name:      "en",
+			extractor: Languages["en"].Extractor,
+			text:      "{{Infobox person\n| name = Zoë O'Brien\n| occupation = [[Actor|actress]]\n}}",
+			want:      []string{"Zoë O'Brien"},
+		},
+		{
+			name:      "en other infobox",
+			extractor: Languages["en"].Extractor,
+			text:      "{{Infobox_scientist\n| name = [[Marie Curie|Marie Skłodowska Curie]]\n}}",
+			want:      []string{"Marie Skłodowska Curie"},
+		},
+		{
+			name:      "no template",
+			extractor: Languages["de"].Extractor,
+			text:      "'''John Doe''' ist eine [[Testperson]].",
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.extractor.Match(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripWikiMarkup(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Doe, John", "Doe, John"},
+		{"Müller, Jürgen", "Müller, Jürgen"},
+		{"", ""},
+		{"[[John Doe]]", "John Doe"},
+		{"[[John Doe (Schauspieler)|Doe, John]]", "Doe, John"},
+		{"Doe, John{{Anker|JD}}", "Doe, John"},
+		{"Doe, John[[Kategorie:Mann]][[Category:Living people]]", "Doe, John"},
+		{"Doe, John{{Anker|{{lang|de|JD}}}}", "Doe, John"},
+		{"Doe, <!-- Kommentar -->John", "Doe, John"},
+		{"Doe, <!-- {{Anker|JD}} -->John<!-- unclosed", "Doe, John"},
+	}
+
+	for _, tt := range tests {
+		if got := StripWikiMarkup(tt.in); got != tt.want {
+			t.Errorf("StripWikiMarkup(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStripNestedTemplates(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"{{Personendaten|NAME=Doe, John}}", "{{Personendaten|NAME=Doe, John}}"},
+		{"{{Personendaten|NAME=Doe, John{{Anker|JD}}}}", "{{Personendaten|NAME=Doe, John}}"},
+		{"{{Personendaten|NAME=Doe, John{{Anker|{{lang|de|JD}}}}|ORT=Berlin}}", "{{Personendaten|NAME=Doe, John|ORT=Berlin}}"},
+		{"{{A}} text {{B|{{C}}}}", "{{A}} text {{B|}}"},
+		{"{{Personendaten|NAME=Doe, <!-- {{ -->John}}", "{{Personendaten|NAME=Doe, John}}"},
+		{"{{Personendaten|NAME=Doe, John{{Anker", "{{Personendaten|NAME=Doe, John"},
+		{"}} text {{A}}", "}} text {{A}}"},
+	}
+
+	for _, tt := range tests {
+		if got := StripNestedTemplates(tt.in); got != tt.want {
+			t.Errorf("StripNestedTemplates(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestInfoboxPersonEN(t *testing.T) {
+	lang := Languages["en"]
+
+	text := `{{Short description|American actress}}
+{{Infobox person
+| name         = Mary Ann Smith
+| image        = Mary Ann Smith 2010.jpg
+| birth_date   = {{birth date and age|1970|5|17}}
+| birth_place  = [[Portland, Oregon]], U.S.
+| occupation   = Actress
+}}
+'''Mary Ann Smith''' (born May 17, 1970) is an American actress.
+
+[[Category:1970 births]]
+[[Category:Living people]]`
+
+	values := lang.Extractor.Match(text)
+	if len(values) != 1 || values[0] != "Mary Ann Smith" {
+		t.Fatalf("got %q, want %q", values, []string{"Mary Ann Smith"})
+	}
+
+	first, last, ok := lang.SplitName(values[0])
+	if !ok || first != "Mary Ann" || last != "Smith" {
+		t.Errorf("got %q, %q, %v, want %q, %q, true", first, last, ok, "Mary Ann", "Smith")
+	}
+
+	if !lang.PersonCategory.MatchString(text) {
+		t.Error("person category not matched")
+	}
+
+	if _, _, ok := lang.SplitName("Madonna"); ok {
+		t.Error("single name split")
+	}
+}
@@ -0,0 +1,414 @@
+package wordlist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	SpecialCharacters = "!$@_"
+	CommonYearsFrom   = 1940
+	CommonYearsTo     = 2029
+	ProgressLines     = 100000
+)
+
+// Name is a single name extracted from the dump.
+type Name struct {
+	First string   // First name
+	Last  string   // Last name (only set in combine mode)
+	Years []string // Birth years of persons with this name, most common first (only set with birth years)
+}
+
+// OutputOptions controls how names are expanded into wordlist entries.
+type OutputOptions struct {
+	Digits            int            // Append up to this many digits
+	SpecialChars      string         // Append special characters from this set
+	Reverse           bool           // Also add names in reversed order
+	CombineSeparators []string       // Separators used to join first and last names
+	TokenSeparators   []string       // Separators used to join multiple first names
+	LineEnding        string         // Terminator written after each entry
+	MaxVariants       int            // Stop after this many entries per name (0 for no limit)
+	NamesOnly         bool           // Write names in each case variant only, without any digit or special character variants
+	Cases             []string       // Case variants written for each name
+	PreserveCase      bool           // Also write each name in its original casing
+	NamePrefix        string         // Prepended to each name after case transformation (i.e. "admin.")
+	NameSuffix        string         // Appended to each name before digits and special characters
+	PhoneticRules     []PhoneticRule // Rules creating phonetic variants of first names (nil to disable)
+	KeyboardWalks     []string       // Keyboard walks appended like digits (i.e. "1qaz")
+	FlushInterval     int            // Flush buffered output after this many entries (0 to flush at the end only)
+	FlushPeriod       time.Duration  // Also flush buffered output in this interval (0 to flush only when the buffer is full)
+	BufferSize        int            // Size of the output buffer in bytes (0 for the default size)
+	Pattern           *EntryPattern  // Order of name, digits, and special character in entries (nil for DefaultPattern)
+	NameFile          NameFileFunc   // Write the entries of each name to its own file created by this function instead (nil to disable)
+	ProgressOutput    bool           // Log the number of entries written every ProgressLines entries
+	Interrupt         chan struct{}  // Closed to stop writing after the current name (nil to write all names)
+	OnError           func(error)    // Called once writing fails, no longer reading names (nil to only set OutputStats.Err)
+}
+
+// NameFileFunc creates the file holding the entries of a single name.
+type NameFileFunc func(name Name) (io.WriteCloser, error)
+
+// OutputStats holds the number of names and entries written by the output routine.
+type OutputStats struct {
+	Names int   // Number of names written
+	Lines int64 // Number of entries written
+	Err   error // First error writing output (nothing is written after it)
+}
+
+// ...
+func OutputRoutine(w io.Writer, opts *OutputOptions, ch chan Name, stats *OutputStats, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	// Buffer output, flushing it periodically (locked, as it may also be flushed by a timer)
+	bw := bufio.NewWriter(w)
+	if opts.BufferSize > 0 {
+		bw = bufio.NewWriterSize(w, opts.BufferSize)
+	}
+
+	mu := &sync.Mutex{}
+
+	// Keep the first error writing output (i.e. a full disk)
+	fail := func(err error) {
+		if err != nil && stats.Err == nil {
+			stats.Err = err
+		}
+	}
+
+	defer func() {
+		mu.Lock()
+		fail(bw.Flush())
+		mu.Unlock()
+	}()
+
+	if opts.FlushPeriod > 0 {
+		done := make(chan struct{})
+		defer close(done)
+
+		go func() {
+			ticker := time.NewTicker(opts.FlushPeriod)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					mu.Lock()
+					fail(bw.Flush())
+					mu.Unlock()
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	// Write the entries of each name to its own file
+	var nameFile io.WriteCloser
+
+	closeNameFile := func() {
+		if nameFile == nil {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		fail(bw.Flush())
+
+		if err := nameFile.Close(); err != nil {
+			fail(fmt.Errorf("unable to write name file: %w", err))
+		}
+
+		nameFile = nil
+	}
+
+	defer closeNameFile()
+
+	openNameFile := func(name Name) {
+		closeNameFile()
+
+		f, err := opts.NameFile(name)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			fail(fmt.Errorf("unable to create name file: %w", err))
+			return
+		}
+
+		nameFile = f
+		bw.Reset(f)
+	}
+
+	var lines, flushed int
+
+	write := func(s string, n int) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if stats.Err != nil {
+			return
+		}
+
+		if _, err := bw.WriteString(s); err != nil {
+			fail(err)
+			return
+		}
+
+		if opts.ProgressOutput && (lines+n)/ProgressLines > lines/ProgressLines {
+			logrus.Infof("Wrote %d entries", (lines+n)/ProgressLines*ProgressLines)
+		}
+
+		lines += n
+		stats.Lines += int64(n)
+
+		if opts.FlushInterval > 0 && lines-flushed >= opts.FlushInterval {
+			fail(bw.Flush())
+			flushed = lines
+		}
+	}
+
+	// Create number and keyboard walk combinations
+	digitCombs := SuffixCombinations(opts.Digits, opts.KeyboardWalks)
+
+	// Create special character combinations
+	charCombs := []string{""}
+
+	for _, c := range opts.SpecialChars {
+		charCombs = append(charCombs, string(c))
+	}
+
+	// Generate output
+	le := opts.LineEnding
+
+	for {
+		var name Name
+		var ok bool
+
+		select {
+		case name, ok = <-ch:
+		case <-opts.Interrupt:
+		}
+
+		if !ok {
+			break
+		}
+
+		// Stop at the first error writing output
+		mu.Lock()
+		err := stats.Err
+		mu.Unlock()
+
+		if err != nil {
+			if opts.OnError != nil {
+				opts.OnError(err)
+			}
+
+			break
+		}
+
+		// Skip empty names (i.e. left over by name transforms)
+		if strings.TrimSpace(name.First) == "" {
+			continue
+		}
+
+		stats.Names++
+
+		if opts.NameFile != nil {
+			openNameFile(name)
+		}
+
+		// Base names, with phonetic variants of the first name
+		bases := CombineName(name, opts.CombineSeparators)
+
+		if len(opts.PhoneticRules) > 0 {
+			seen := make(map[string]bool)
+			for _, b := range bases {
+				seen[b] = true
+			}
+
+			// Skip duplicates, i.e. initials of "Karl" and "Karel"
+			for _, first := range PhoneticVariants(name.First, opts.PhoneticRules) {
+				for _, b := range CombineName(Name{First: first, Last: name.Last}, opts.CombineSeparators) {
+					if !seen[b] {
+						seen[b] = true
+						bases = append(bases, b)
+					}
+				}
+			}
+		}
+
+		// Reversed names, skipping palindromes (i.e. "anna") and reversals of other base names
+		if opts.Reverse {
+			seen := make(map[string]bool, 2*len(bases))
+			for _, b := range bases {
+				seen[b] = true
+			}
+
+			for _, b := range bases {
+				if r := ReverseString(b); !seen[r] {
+					seen[r] = true
+					bases = append(bases, r)
+				}
+			}
+		}
+
+		// Lower, upper, and title case (or as selected)
+		var words []string
+		for _, base := range bases {
+			words = append(words, CaseVariants(base, opts.Cases, opts.PreserveCase)...)
+		}
+
+		// Join multiple first names with each separator (i.e. "anna_maria" or "AnnaMaria")
+		if strings.Contains(name.First, " ") {
+			words = JoinVariants(words, opts.TokenSeparators)
+		}
+
+		// Add prefix and suffix (not affected by case)
+		if opts.NamePrefix != "" || opts.NameSuffix != "" {
+			for i, word := range words {
+				words[i] = opts.NamePrefix + word + opts.NameSuffix
+			}
+		}
+
+		// Write names only, without digits and special characters
+		if opts.NamesOnly {
+			var sb strings.Builder
+			for _, word := range words {
+				sb.WriteString(word + le)
+			}
+
+			write(sb.String(), len(words))
+			continue
+		}
+
+		// Append digits (or the birth years of persons with the name instead) and special characters, most
+		// likely combinations first
+		suffixes := digitCombs
+		if len(name.Years) > 0 && (opts.Pattern == nil || opts.Pattern.Has(PatternDigits)) {
+			suffixes = append([]string{""}, name.Years...)
+		}
+
+		limit := opts.MaxVariants
+
+	Variants:
+		for _, d := range suffixes {
+			for _, c := range charCombs {
+				ws := words
+				if opts.MaxVariants > 0 {
+					if limit <= 0 {
+						break Variants
+					}
+
+					if limit < len(ws) {
+						ws = ws[:limit]
+					}
+
+					limit -= len(ws)
+				}
+
+				var sb strings.Builder
+				for _, word := range ws {
+					if opts.Pattern != nil {
+						opts.Pattern.Write(&sb, word, d, c)
+						sb.WriteString(le)
+					} else {
+						sb.WriteString(word + d + c + le)
+					}
+				}
+
+				write(sb.String(), len(ws))
+			}
+		}
+	}
+}
+
+// DigitCombinations returns all digit suffixes with up to the given number of digits, ordered by their
+// likelihood: shorter suffixes first, but common years before any other suffix with 3 or more digits.
+func DigitCombinations(digits int) []string {
+	combs := []string{""}
+
+	// Common years
+	var years []string
+	if digits >= 4 {
+		for y := CommonYearsFrom; y <= CommonYearsTo; y++ {
+			years = append(years, strconv.Itoa(y))
+		}
+	}
+
+	maxNumber := 1
+	for d := 0; d < digits; d++ {
+		maxNumber *= 10
+		format := fmt.Sprintf("%%0%dd", d+1)
+
+		if d == 2 {
+			combs = append(combs, years...)
+		}
+
+		for i := 0; i < maxNumber; i++ {
+			if d == 3 && i >= CommonYearsFrom && i <= CommonYearsTo {
+				continue
+			}
+
+			combs = append(combs, fmt.Sprintf(format, i))
+		}
+	}
+
+	return combs
+}
+
+// CombineName returns the base names for n. If n has a last name, the first name and its initial are
+// joined with the last name using each of the given separators (i.e. "john.doe" or "jdoe").
+func CombineName(n Name, separators []string) []string {
+	if n.Last == "" {
+		return []string{n.First}
+	}
+
+	initial := string([]rune(n.First)[:1])
+
+	var bases []string
+	for _, sep := range separators {
+		bases = append(bases, n.First+sep+n.Last, initial+sep+n.Last)
+	}
+
+	return bases
+}
+
+// JoinVariants replaces the spaces between multiple first names in words with each of the given separators,
+// skipping duplicates.
+func JoinVariants(words []string, separators []string) []string {
+	if len(separators) == 0 {
+		separators = []string{""}
+	}
+
+	var res []string
+	seen := make(map[string]bool)
+
+	for _, word := range words {
+		for _, sep := range separators {
+			if w := strings.ReplaceAll(word, " ", sep); !seen[w] {
+				seen[w] = true
+				res = append(res, w)
+			}
+		}
+	}
+
+	return res
+}
+
+// ReverseString returns s with its runes in reversed order.
+func ReverseString(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+
+	return string(r)
+}
@@ -0,0 +1,179 @@
+package wordlist
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// runOutput writes the given names with OutputRoutine and returns the entries written.
+func runOutput(t *testing.T, opts *OutputOptions, names ...Name) []string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var stats OutputStats
+	var wg sync.WaitGroup
+
+	ch := make(chan Name, len(names))
+	for _, name := range names {
+		ch <- name
+	}
+	close(ch)
+
+	wg.Add(1)
+	OutputRoutine(&buf, opts, ch, &stats, &wg)
+	wg.Wait()
+
+	if stats.Err != nil {
+		t.Fatalf("unexpected error: %v", stats.Err)
+	}
+
+	// Empty names are skipped
+	var want int
+	for _, name := range names {
+		if strings.TrimSpace(name.First) != "" {
+			want++
+		}
+	}
+
+	if stats.Names != want {
+		t.Errorf("got %d names, want %d", stats.Names, want)
+	}
+
+	lines := strings.SplitAfter(buf.String(), opts.LineEnding)
+	lines = lines[:len(lines)-1]
+
+	if stats.Lines != int64(len(lines)) {
+		t.Errorf("got %d lines counted, want %d", stats.Lines, len(lines))
+	}
+
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, opts.LineEnding)
+	}
+
+	return lines
+}
+
+func TestOutputRoutine(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  OutputOptions
+		input Name
+		want  []string
+	}{
+		{
+			name:  "cases",
+			opts:  OutputOptions{Cases: DefaultCases},
+			input: Name{First: "anna"},
+			want:  []string{"anna", "ANNA", "Anna"},
+		},
+		{
+			name:  "selected case",
+			opts:  OutputOptions{Cases: []string{"upper"}},
+			input: Name{First: "Zoë"},
+			want:  []string{"ZOË"},
+		},
+		{
+			name:  "digits",
+			opts:  OutputOptions{Cases: []string{"lower"}, Digits: 1},
+			input: Name{First: "anna"},
+			want:  []string{"anna", "anna0", "anna1", "anna2", "anna3", "anna4", "anna5", "anna6", "anna7", "anna8", "anna9"},
+		},
+		{
+			name:  "special characters",
+			opts:  OutputOptions{Cases: []string{"lower", "title"}, SpecialChars: "!$"},
+			input: Name{First: "anna"},
+			want:  []string{"anna", "Anna", "anna!", "Anna!", "anna$", "Anna$"},
+		},
+		{
+			name:  "digits before special characters",
+			opts:  OutputOptions{Cases: []string{"lower"}, Digits: 1, SpecialChars: "!"},
+			input: Name{First: "otto"},
+			want: []string{
+				"otto", "otto!", "otto0", "otto0!", "otto1", "otto1!", "otto2", "otto2!", "otto3", "otto3!",
+				"otto4", "otto4!", "otto5", "otto5!", "otto6", "otto6!", "otto7", "otto7!", "otto8", "otto8!",
+				"otto9", "otto9!",
+			},
+		},
+		{
+			name:  "reverse",
+			opts:  OutputOptions{Cases: []string{"lower"}, Reverse: true},
+			input: Name{First: "jan"},
+			want:  []string{"jan", "naj"},
+		},
+		{
+			name:  "reverse palindrome",
+			opts:  OutputOptions{Cases: []string{"lower"}, Reverse: true},
+			input: Name{First: "anna"},
+			want:  []string{"anna"},
+		},
+		{
+			name:  "names only",
+			opts:  OutputOptions{Cases: []string{"lower", "title"}, Digits: 2, SpecialChars: "!", NamesOnly: true},
+			input: Name{First: "anna"},
+			want:  []string{"anna", "Anna"},
+		},
+		{
+			name:  "names only as extracted",
+			opts:  OutputOptions{PreserveCase: true, NamesOnly: true, NameSuffix: "."},
+			input: Name{First: "McDonald"},
+			want:  []string{"McDonald."},
+		},
+		{
+			name:  "names only joined",
+			opts:  OutputOptions{Cases: []string{"lower"}, TokenSeparators: []string{"", "_"}, NamesOnly: true},
+			input: Name{First: "Anna Maria"},
+			want:  []string{"annamaria", "anna_maria"},
+		},
+		{
+			name:  "empty name",
+			opts:  OutputOptions{Cases: []string{"lower"}, SpecialChars: "!"},
+			input: Name{},
+			want:  []string{},
+		},
+		{
+			name:  "blank name",
+			opts:  OutputOptions{Cases: []string{"lower"}, Digits: 1, NameSuffix: "."},
+			input: Name{First: " \t"},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.LineEnding = "\n"
+
+			got := runOutput(t, &tt.opts, tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputRoutineDigitCount(t *testing.T) {
+	opts := &OutputOptions{Cases: DefaultCases, Digits: 2, LineEnding: "\n"}
+
+	// Base name, 10 one-digit and 100 two-digit suffixes in 3 cases each
+	if got := runOutput(t, opts, Name{First: "anna"}); len(got) != 3*111 {
+		t.Errorf("got %d entries, want %d", len(got), 3*111)
+	}
+}
+
+func TestSuffixCombinations(t *testing.T) {
+	walks := []string{"1qaz", "qwerty"}
+
+	// Walks follow the suffixes of up to two digits
+	combs := SuffixCombinations(4, walks)
+	if combs[ShortDigitSuffixes-1] != "99" || combs[ShortDigitSuffixes] != "1qaz" || combs[ShortDigitSuffixes+2] != strconv.Itoa(CommonYearsFrom) {
+		t.Errorf("walks not placed after two digits: %q", combs[ShortDigitSuffixes-1:ShortDigitSuffixes+3])
+	}
+
+	// All suffixes if there are fewer digits
+	if combs := SuffixCombinations(1, walks); !reflect.DeepEqual(combs[len(combs)-3:], []string{"9", "1qaz", "qwerty"}) {
+		t.Errorf("walks not placed at the end: %q", combs)
+	}
+}
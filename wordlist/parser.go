@@ -0,0 +1,436 @@
+package wordlist
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProgressPages is the number of pages between calls of DumpParser.Progress.
+const ProgressPages = 10000
+
+// DumpParser extracts names from the person data templates of a Wikipedia dump.
+type DumpParser struct {
+	Language  *Language      // Language of the dump
+	Stopwords Stopwords      // Tokens that are skipped when picking first names
+	Combine   bool           // Also extract last names
+	Namespace string         // Skip pages outside this namespace ("0" for articles)
+	Sample    int            // Stop after this many pages (0 for all)
+	Reservoir *Reservoir     // Sample pages at random from the whole dump instead (nil to disable)
+	Deadline  time.Time      // Stop at this time (zero for no deadline)
+	Skip      int            // Skip this many pages without extracting names (i.e. when resuming)
+	Lock      *sync.RWMutex  // Read-locked while processing a page (nil for no locking)
+	Progress  func()         // Called every ProgressPages pages (nil to disable)
+	Counters  *ParseCounters // Updated after each page for progress bars (nil to disable)
+	CamelCase bool           // Split CamelCase values without separator (i.e. "JohnDoe")
+	Joined    bool           // Keep all of multiple first names (joined by space) instead of the first one
+	Words     int            // Count up to this many of multiple first names on their own (-1 for all, ignored if joined)
+	Strict    bool           // Abort on the first page that cannot be decoded
+
+	ExcludeRedirects bool // Skip redirect pages
+	TitleFallback    bool // Read the name from the title of pages in person categories without person data
+	BirthYears       bool // Pass the birth year from the person data along with names
+
+	Redirects map[string]int // Number of redirects to pages by title, counting their names once more each (nil to disable)
+
+	Transforms NameTransforms // Applied to template values and titles before splitting them into names (nil to disable)
+
+	TemplateDebug func(title string, template string)                     // Called with each template matched, before parsing it (nil to disable)
+	FieldDebug    func(title string, index int, key string, value string) // Called with each field of the index-th template matched (nil to disable)
+
+	Pages    int // Number of pages processed so far
+	Names    int // Number of first names extracted so far
+	Failures int // Number of pages that could not be decoded
+
+	PersonPages    int // Number of pages with names extracted from person data
+	MalformedPages int // Number of pages with person data but no name extracted from it
+}
+
+// ParseCounters holds the number of pages and names of a running parser. Both are updated and must be read
+// atomically.
+type ParseCounters struct {
+	Pages int64 // Number of pages processed so far
+	Names int64 // Number of first names extracted so far
+}
+
+// Parse reads a Wikipedia XML dump from r and calls emit for each first name found. In combine mode, emit
+// is called a second time with the last name set.
+func (dp *DumpParser) Parse(r io.Reader, emit func(Name)) error {
+	decoder := xml.NewDecoder(r)
+	for dp.more() {
+		token, err := decoder.Token()
+		if token == nil || err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("unable to decode XML token: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "page" {
+				// Skip pages processed before resuming
+				if dp.Pages < dp.Skip {
+					if dp.Lock != nil {
+						dp.Lock.RLock()
+						dp.Pages++
+						dp.Lock.RUnlock()
+					} else {
+						dp.Pages++
+					}
+
+					if err := decoder.Skip(); err != nil {
+						return fmt.Errorf("unable to skip page: %w", err)
+					}
+
+					continue
+				}
+
+				if err := dp.parseElement(decoder, &t, emit); err != nil {
+					return err
+				}
+
+				dp.count()
+			}
+		default:
+		}
+	}
+
+	dp.parseReservoir(emit)
+	dp.count()
+
+	return nil
+}
+
+// count updates the counters of the parser.
+func (dp *DumpParser) count() {
+	if dp.Counters != nil {
+		atomic.StoreInt64(&dp.Counters.Pages, int64(dp.Pages))
+		atomic.StoreInt64(&dp.Counters.Names, int64(dp.Names))
+	}
+}
+
+// parseElement decodes a single <page> element and extracts names from it. Pages that cannot be decoded are
+// counted and skipped, unless in strict mode.
+func (dp *DumpParser) parseElement(decoder *xml.Decoder, start *xml.StartElement, emit func(Name)) error {
+	if dp.Lock != nil {
+		dp.Lock.RLock()
+		defer dp.Lock.RUnlock()
+	}
+
+	dp.Pages++
+
+	if dp.Progress != nil && dp.Pages%ProgressPages == 0 {
+		dp.Progress()
+	}
+
+	// Decode <page> element
+	var p WikipediaPage
+
+	if err := decoder.DecodeElement(&p, start); err != nil {
+		dp.Failures++
+
+		if dp.Strict {
+			return fmt.Errorf("unable to decode page %d: %w", dp.Pages, err)
+		}
+
+		logrus.Debugf("Unable to decode page %d, skipping it: %v", dp.Pages, err)
+		return nil
+	}
+
+	// Skip if not in selected namespace (i.e. talk or user pages)
+	if p.Namespace != dp.Namespace {
+		return nil
+	}
+
+	// Skip redirects (they only link to the actual article)
+	if dp.ExcludeRedirects && p.Redirect != nil {
+		return nil
+	}
+
+	// Skip if no or empty revision
+	rev := p.LatestRevision()
+	if rev == nil {
+		return nil
+	}
+
+	dp.parsePage(p.Title, rev.Text, emit)
+
+	return nil
+}
+
+// more returns true if the parser should continue with the next page.
+func (dp *DumpParser) more() bool {
+	if dp.Sample > 0 && dp.Reservoir == nil && dp.Pages >= dp.Sample {
+		return false
+	}
+
+	return dp.Deadline.IsZero() || time.Now().Before(dp.Deadline)
+}
+
+// parsePage extracts names from the text of a single page, or adds the page to the reservoir when sampling
+// at random.
+func (dp *DumpParser) parsePage(title string, text string, emit func(Name)) {
+	if dp.Reservoir != nil {
+		dp.Reservoir.Add(SamplePage{Title: title, Text: text})
+		return
+	}
+
+	dp.parseText(title, text, emit)
+}
+
+// parseReservoir extracts names from all pages sampled at random.
+func (dp *DumpParser) parseReservoir(emit func(Name)) {
+	if dp.Reservoir == nil {
+		return
+	}
+
+	for _, p := range dp.Reservoir.Pages() {
+		dp.parseText(p.Title, p.Text, emit)
+	}
+}
+
+// parseText extracts names from all person data templates in the text of a single page. The title is only used
+// for trace logging, or as the name if there are no templates and title fallback is enabled.
+func (dp *DumpParser) parseText(title string, text string, emit func(Name)) {
+	trace := logrus.IsLevelEnabled(logrus.TraceLevel)
+
+	if dp.TemplateDebug != nil || dp.FieldDebug != nil {
+		for i, tmpl := range dp.Language.Extractor.Templates(text) {
+			if dp.TemplateDebug != nil {
+				dp.TemplateDebug(title, tmpl)
+			}
+
+			if dp.FieldDebug != nil {
+				for _, f := range dp.Language.Extractor.TemplateFields(tmpl) {
+					dp.FieldDebug(title, i, f.Key, f.Value)
+				}
+			}
+		}
+	}
+
+	values := dp.Language.Extractor.Match(text)
+	if len(values) == 0 && dp.TitleFallback {
+		dp.parseTitle(title, text, emit)
+		return
+	}
+
+	names := dp.Names
+
+	defer func() {
+		if dp.Names > names {
+			dp.PersonPages++
+		} else if len(values) > 0 {
+			dp.MalformedPages++
+		}
+	}()
+
+	// Birth year of the person
+	var years []string
+
+	if dp.BirthYears && dp.Language.BirthDate != nil {
+		if year := BirthYear(dp.Language.BirthDate.Match(text)); year != "" {
+			years = []string{year}
+		}
+	}
+
+	// Count each first name at most once per page, even if it has multiple (or duplicate) templates
+	seen := make(map[string]bool, len(values))
+
+	for _, value := range values {
+		// Split last- and firstname (after transforming the whole value)
+		first, last, ok := dp.splitName(dp.Transforms.Apply(value))
+		if !ok {
+			if trace {
+				logrus.Tracef("Page %q: skipping %q, no separator between last and first name", title, value)
+			}
+
+			continue
+		}
+
+		// Split multiple firstnames and pick the first ones that are not stopwords (or all of them joined)
+		firstnames := PickFirstnames(first, dp.Stopwords, dp.Joined, dp.Words)
+		if len(firstnames) == 0 {
+			if trace {
+				logrus.Tracef("Page %q: skipping %q, no first name left after stopwords", title, value)
+			}
+
+			continue
+		}
+
+		lastname := strings.Join(FirstnameSeperatorRegExp.Split(last, -1), "")
+
+		for _, firstname := range firstnames {
+			if seen[firstname] {
+				if trace {
+					logrus.Tracef("Page %q: skipping %q, first name %q already counted for this page", title, value, firstname)
+				}
+
+				continue
+			}
+
+			seen[firstname] = true
+
+			if trace {
+				logrus.Tracef("Page %q: matched %q, parsed first name %q and last name %q", title, value, firstname, lastname)
+			}
+
+			dp.emitName(title, firstname, lastname, years, emit)
+		}
+	}
+}
+
+// parseTitle extracts the name from the title of a page without person data templates, if the page is in a
+// person category of the language (i.e. births). Titles are read as "Firstname Lastname", without any
+// disambiguation suffix (i.e. "John Doe (Musiker)").
+func (dp *DumpParser) parseTitle(title string, text string, emit func(Name)) {
+	if dp.Language.PersonCategory == nil || !dp.Language.PersonCategory.MatchString(text) {
+		return
+	}
+
+	name := FirstLastSeparatorRegExp.Split(strings.TrimSpace(dp.Transforms.Apply(TitleDisambiguationRegExp.ReplaceAllString(title, ""))), -1)
+	if len(name) < 2 || strings.ContainsAny(title, ",:") {
+		return
+	}
+
+	lastname := strings.Join(FirstnameSeperatorRegExp.Split(name[len(name)-1], -1), "")
+
+	for _, firstname := range PickFirstnames(strings.Join(name[:len(name)-1], " "), dp.Stopwords, dp.Joined, dp.Words) {
+		if logrus.IsLevelEnabled(logrus.TraceLevel) {
+			logrus.Tracef("Page %q: no person data, parsed first name %q and last name %q from title", title, firstname, lastname)
+		}
+
+		dp.emitName(title, firstname, lastname, nil, emit)
+	}
+}
+
+// emitName counts a single name extracted from the page with the given title and emits it together with the
+// birth years of the person (in combine mode, also combined with the last name). If redirects are followed,
+// the name is emitted once more for each redirect to the page.
+func (dp *DumpParser) emitName(title string, firstname string, lastname string, years []string, emit func(Name)) {
+	dp.Names++
+
+	for i := 0; i <= dp.Redirects[title]; i++ {
+		emit(Name{First: firstname, Years: years})
+
+		// Combine with last name
+		if dp.Combine && lastname != "" {
+			emit(Name{First: firstname, Last: lastname, Years: years})
+		}
+	}
+}
+
+// BirthYear returns the first year found in the given birth dates (i.e. "1975" for "3. März 1975"), or an
+// empty string if there is none.
+func BirthYear(dates []string) string {
+	for _, date := range dates {
+		if year := BirthYearRegExp.FindString(date); year != "" {
+			return year
+		}
+	}
+
+	return ""
+}
+
+// splitName splits a template value into first and last name. In CamelCase mode, values without separator
+// are split at their inner capitals, in the name order of the language.
+func (dp *DumpParser) splitName(value string) (string, string, bool) {
+	first, last, ok := dp.Language.SplitName(value)
+	if ok || !dp.CamelCase {
+		return first, last, ok
+	}
+
+	parts := SplitCamelCase(strings.TrimSpace(value))
+	if len(parts) < 2 {
+		return "", "", false
+	}
+
+	if dp.Language.NameOrder == NameOrderFirstLast {
+		return strings.Join(parts[:len(parts)-1], " "), parts[len(parts)-1], true
+	}
+
+	return strings.Join(parts[1:], " "), parts[0], true
+}
+
+// SplitCamelCase splits s before each upper case letter that follows a lower case letter (i.e. "JohnDoe"
+// into "John" and "Doe"). Strings containing anything but letters, or that would be split into parts shorter
+// than 3 letters (i.e. "McDonald" or "JoAnn"), are not split.
+func SplitCamelCase(s string) []string {
+	var parts []string
+	var prev rune
+
+	start := 0
+	for i, r := range s {
+		if !unicode.IsLetter(r) {
+			return []string{s}
+		}
+
+		if unicode.IsUpper(r) && unicode.IsLower(prev) {
+			parts = append(parts, s[start:i])
+			start = i
+		}
+
+		prev = r
+	}
+
+	parts = append(parts, s[start:])
+
+	for _, p := range parts {
+		if utf8.RuneCountInString(p) < 3 {
+			return []string{s}
+		}
+	}
+
+	return parts
+}
+
+// PickFirstname splits s into multiple first names and returns the first one that is not a stopword, or an
+// empty string if there is none. If joined is set, all of them are returned, separated by a space.
+func PickFirstname(s string, stopwords Stopwords, joined bool) string {
+	var toks []string
+
+	for _, tok := range FirstnameSeperatorRegExp.Split(s, -1) {
+		if tok != "" && !stopwords.Contains(tok) {
+			if !joined {
+				return tok
+			}
+
+			toks = append(toks, tok)
+		}
+	}
+
+	return strings.Join(toks, " ")
+}
+
+// PickFirstnames splits s into multiple first names and returns up to n of them (all of them if n is negative)
+// that are not stopwords, each to be counted on its own. If joined is set, the single first name returned by
+// PickFirstname is returned instead.
+func PickFirstnames(s string, stopwords Stopwords, joined bool, n int) []string {
+	if joined || n == 0 || n == 1 {
+		if firstname := PickFirstname(s, stopwords, joined); firstname != "" {
+			return []string{firstname}
+		}
+
+		return nil
+	}
+
+	var toks []string
+
+	for _, tok := range FirstnameSeperatorRegExp.Split(s, -1) {
+		if tok != "" && !stopwords.Contains(tok) {
+			toks = append(toks, tok)
+			if len(toks) == n {
+				break
+			}
+		}
+	}
+
+	return toks
+}
@@ -0,0 +1,153 @@
+package wordlist
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// parseDump runs a German DumpParser over the given XML dump and returns the parser and the names emitted.
+func parseDump(t *testing.T, dump string) (*DumpParser, []Name) {
+	t.Helper()
+
+	dp := &DumpParser{
+		Language:  Languages["de"],
+		Stopwords: NewStopwords(),
+		Namespace: "0",
+		Combine:   true,
+	}
+
+	var names []Name
+	if err := dp.Parse(strings.NewReader(dump), func(n Name) { names = append(names, n) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return dp, names
+}
+
+func TestDumpParserLatestRevision(t *testing.T) {
+	dump := `<mediawiki>
+<page><title>John Doe</title><ns>0</ns><id>1</id>
+<revision><id>10</id><text>{{Personendaten|NAME=Doe, Johnny}}</text></revision>
+<revision><id>12</id><text>{{Personendaten|NAME=Doe, John}}</text></revision>
+<revision><id>11</id><text>{{Personendaten|NAME=Doe, Jonathan}}</text></revision>
+</page>
+</mediawiki>`
+
+	dp, names := parseDump(t, dump)
+
+	want := []Name{{First: "John"}, {First: "John", Last: "Doe"}}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+
+	if dp.Pages != 1 || dp.Names != 1 {
+		t.Errorf("got %d pages and %d names, want 1 and 1", dp.Pages, dp.Names)
+	}
+}
+
+func TestDumpParserDuplicateTemplate(t *testing.T) {
+	dump := `<mediawiki>
+<page><title>John Doe</title><ns>0</ns><id>1</id>
+<revision><id>10</id><text>{{Personendaten
+|NAME=Doe, John
+}}
+'''John Doe''' ist eine Testperson.
+{{Personendaten
+|NAME=Doe, John
+}}</text></revision>
+</page>
+</mediawiki>`
+
+	dp, names := parseDump(t, dump)
+
+	want := []Name{{First: "John"}, {First: "John", Last: "Doe"}}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+
+	if dp.Names != 1 || dp.PersonPages != 1 {
+		t.Errorf("got %d names on %d person pages, want 1 and 1", dp.Names, dp.PersonPages)
+	}
+}
+
+func TestDumpParserNestedMarkup(t *testing.T) {
+	dump := `<mediawiki>
+<page><title>John Doe</title><ns>0</ns><id>1</id><revision><id>10</id><text>{{Personendaten
+|NAME=Doe, John {{Anker|{{lang|en|JD}}}}
+}}</text></revision></page>
+<page><title>Jane Roe</title><ns>0</ns><id>2</id><revision><id>11</id><text>{{Personendaten
+|NAME=Roe, <!-- Kommentar -->Jane
+}}</text></revision></page>
+</mediawiki>`
+
+	_, names := parseDump(t, dump)
+
+	want := []Name{{First: "John"}, {First: "John", Last: "Doe"}, {First: "Jane"}, {First: "Jane", Last: "Roe"}}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
+
+func TestDumpParserTransforms(t *testing.T) {
+	tests := []struct {
+		name       string
+		transforms NameTransforms
+		value      string
+		want       []Name
+	}{
+		{
+			name:  "none",
+			value: "Schmidt-Meier, Anna-Maria",
+			want:  []Name{{First: "Anna"}, {First: "Anna", Last: "SchmidtMeier"}},
+		},
+		{
+			name:       "collapse hyphen",
+			transforms: NameTransforms{{From: "-", To: ""}},
+			value:      "Schmidt-Meier, Anna-Maria",
+			want:       []Name{{First: "AnnaMaria"}, {First: "AnnaMaria", Last: "SchmidtMeier"}},
+		},
+		{
+			name:       "hyphenated first names",
+			transforms: NameTransforms{{From: "Hans-Peter", To: "Hanspeter"}},
+			value:      "Doe, Hans-Peter",
+			want:       []Name{{First: "Hanspeter"}, {First: "Hanspeter", Last: "Doe"}},
+		},
+		{
+			name:       "applied in order",
+			transforms: NameTransforms{{From: "-", To: " "}, {From: "Anna Maria", To: "Annamaria"}},
+			value:      "Schmidt, Anna-Maria",
+			want:       []Name{{First: "Annamaria"}, {First: "Annamaria", Last: "Schmidt"}},
+		},
+		{
+			name:       "empty first name",
+			transforms: NameTransforms{{From: "John", To: ""}},
+			value:      "Doe, John",
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dp := &DumpParser{
+				Language:   Languages["de"],
+				Stopwords:  NewStopwords(),
+				Namespace:  "0",
+				Combine:    true,
+				Transforms: tt.transforms,
+			}
+
+			dump := `<mediawiki><page><title>Test</title><ns>0</ns><id>1</id><revision><id>10</id><text>{{Personendaten|NAME=` +
+				tt.value + `}}</text></revision></page></mediawiki>`
+
+			var names []Name
+			if err := dp.Parse(strings.NewReader(dump), func(n Name) { names = append(names, n) }); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("got %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package wordlist
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Placeholders in entry patterns.
+const (
+	PatternName    = "{name}"    // Name in its case variant
+	PatternDigits  = "{digits}"  // Appended digits (or keyboard walk)
+	PatternSpecial = "{special}" // Special character
+)
+
+// DefaultPattern is the pattern of entries if none is given (i.e. "john123!").
+const DefaultPattern = PatternName + PatternDigits + PatternSpecial
+
+// EntryPattern describes how the name, digits, and special character of an entry are put together.
+type EntryPattern struct {
+	parts []string // Placeholders and literal text in order
+}
+
+// ParseEntryPattern parses a pattern like "{name}{special}{digits}". The name placeholder must be given exactly
+// once, the others at most once; any other text is kept literally.
+func ParseEntryPattern(s string) (*EntryPattern, error) {
+	ep := &EntryPattern{}
+	seen := make(map[string]bool)
+
+	for len(s) > 0 {
+		start := strings.Index(s, "{")
+		if start < 0 {
+			ep.parts = append(ep.parts, s)
+			break
+		}
+
+		if start > 0 {
+			ep.parts = append(ep.parts, s[:start])
+		}
+
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated placeholder: %s", s[start:])
+		}
+
+		placeholder := s[start : start+end+1]
+		if placeholder != PatternName && placeholder != PatternDigits && placeholder != PatternSpecial {
+			return nil, fmt.Errorf("unknown placeholder: %s", placeholder)
+		}
+
+		if seen[placeholder] {
+			return nil, fmt.Errorf("placeholder given twice: %s", placeholder)
+		}
+
+		seen[placeholder] = true
+		ep.parts = append(ep.parts, placeholder)
+		s = s[start+end+1:]
+	}
+
+	if !seen[PatternName] {
+		return nil, fmt.Errorf("missing placeholder: %s", PatternName)
+	}
+
+	return ep, nil
+}
+
+// Write writes the entry for the given name, digits, and special character to sb.
+func (ep *EntryPattern) Write(sb *strings.Builder, name string, digits string, special string) {
+	for _, part := range ep.parts {
+		switch part {
+		case PatternName:
+			sb.WriteString(name)
+		case PatternDigits:
+			sb.WriteString(digits)
+		case PatternSpecial:
+			sb.WriteString(special)
+		default:
+			sb.WriteString(part)
+		}
+	}
+}
+
+// Has returns true if the pattern holds the given placeholder.
+func (ep *EntryPattern) Has(placeholder string) bool {
+	for _, part := range ep.parts {
+		if part == placeholder {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LiteralLength returns the length of the literal text in the pattern in bytes.
+func (ep *EntryPattern) LiteralLength() int {
+	var n int
+
+	for _, part := range ep.parts {
+		if part != PatternName && part != PatternDigits && part != PatternSpecial {
+			n += len(part)
+		}
+	}
+
+	return n
+}
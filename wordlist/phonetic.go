@@ -0,0 +1,76 @@
+package wordlist
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+)
+
+// PhoneticRule replaces matches of a regular expression in a name to create a phonetically similar variant.
+type PhoneticRule struct {
+	Pattern *regexp.Regexp // Matches the part of the name to replace
+	Replace string         // Replacement, may refer to submatches (i.e. "${1}")
+}
+
+// PhoneticRuleConfig is the definition of a single rule in a phonetic rules file.
+type PhoneticRuleConfig struct {
+	Pattern string `yaml:"pattern"` // Regular expression
+	Replace string `yaml:"replace"` // Replacement
+}
+
+// DefaultPhoneticRules holds the rules used if no rules file is given.
+var DefaultPhoneticRules = []PhoneticRule{
+	{Pattern: regexp.MustCompile(`^K`), Replace: "C"},
+	{Pattern: regexp.MustCompile(`^C`), Replace: "K"},
+	{Pattern: regexp.MustCompile(`ph`), Replace: "f"},
+	{Pattern: regexp.MustCompile(`f`), Replace: "ph"},
+	{Pattern: regexp.MustCompile(`th`), Replace: "t"},
+	{Pattern: regexp.MustCompile(`ck`), Replace: "k"},
+	{Pattern: regexp.MustCompile(`y`), Replace: "i"},
+	{Pattern: regexp.MustCompile(`i`), Replace: "y"},
+}
+
+// LoadPhoneticRules reads phonetic rules in YAML format from r.
+func LoadPhoneticRules(r io.Reader) ([]PhoneticRule, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []PhoneticRuleConfig
+	if err := yaml.UnmarshalStrict(data, &configs); err != nil {
+		return nil, err
+	}
+
+	rules := make([]PhoneticRule, len(configs))
+	for i, cfg := range configs {
+		pattern, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in rule %d: %w", i+1, err)
+		}
+
+		rules[i] = PhoneticRule{Pattern: pattern, Replace: cfg.Replace}
+	}
+
+	return rules, nil
+}
+
+// PhoneticVariants applies each rule to name on its own and returns all distinct variants that differ from
+// name.
+func PhoneticVariants(name string, rules []PhoneticRule) []string {
+	var variants []string
+	seen := map[string]bool{name: true}
+
+	for _, rule := range rules {
+		v := rule.Pattern.ReplaceAllString(name, rule.Replace)
+		if !seen[v] {
+			seen[v] = true
+			variants = append(variants, v)
+		}
+	}
+
+	return variants
+}
@@ -0,0 +1,45 @@
+package wordlist
+
+import (
+	"math/rand"
+)
+
+// SamplePage is the title and text of a single page held in a reservoir.
+type SamplePage struct {
+	Title string // Title of the page
+	Text  string // Wiki text of the page
+}
+
+// Reservoir samples a fixed number of pages uniformly at random from a stream of unknown length, using
+// Vitter's Algorithm R.
+type Reservoir struct {
+	Size int        // Number of pages to sample
+	Rand *rand.Rand // Source of randomness
+
+	pages []SamplePage // Pages sampled so far
+	seen  int          // Number of pages offered so far
+}
+
+// NewReservoir returns a reservoir of the given size whose samples are determined by seed.
+func NewReservoir(size int, seed int64) *Reservoir {
+	return &Reservoir{
+		Size: size,
+		Rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Add offers a page to the reservoir, which keeps it with probability Size / number of pages offered.
+func (r *Reservoir) Add(p SamplePage) {
+	r.seen++
+
+	if len(r.pages) < r.Size {
+		r.pages = append(r.pages, p)
+	} else if i := r.Rand.Intn(r.seen); i < r.Size {
+		r.pages[i] = p
+	}
+}
+
+// Pages returns the sampled pages.
+func (r *Reservoir) Pages() []SamplePage {
+	return r.pages
+}
@@ -0,0 +1,69 @@
+package wordlist
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+var SQLiteTableRegExp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// CountSQLite returns the number of rows in the given table of a database of page texts.
+func CountSQLite(db *sql.DB, table string) (int64, error) {
+	if !SQLiteTableRegExp.MatchString(table) {
+		return 0, fmt.Errorf("invalid table name: %s", table)
+	}
+
+	var rows int64
+	if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&rows); err != nil {
+		return 0, fmt.Errorf("unable to count rows: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ParseSQLite reads page texts from the (page_id, text) columns of the given table and calls emit for each
+// first name found, just like Parse does for XML dumps. Progress is reported to step once per row.
+func (dp *DumpParser) ParseSQLite(db *sql.DB, table string, step func(), emit func(Name)) error {
+	if !SQLiteTableRegExp.MatchString(table) {
+		return fmt.Errorf("invalid table name: %s", table)
+	}
+
+	rows, err := db.Query("SELECT page_id, text FROM " + table + " ORDER BY page_id")
+	if err != nil {
+		return fmt.Errorf("unable to query pages: %w", err)
+	}
+
+	defer rows.Close()
+
+	for dp.more() && rows.Next() {
+		var id int64
+		var text sql.NullString
+
+		if err := rows.Scan(&id, &text); err != nil {
+			return fmt.Errorf("unable to scan page: %w", err)
+		}
+
+		dp.Pages++
+		step()
+
+		if dp.Progress != nil && dp.Pages%ProgressPages == 0 {
+			dp.Progress()
+		}
+
+		if text.Valid {
+			dp.parsePage(fmt.Sprintf("#%d", id), text.String, emit)
+		}
+
+		dp.count()
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("unable to read pages: %w", err)
+	}
+
+	dp.parseReservoir(emit)
+	dp.count()
+
+	return nil
+}
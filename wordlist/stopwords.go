@@ -0,0 +1,57 @@
+package wordlist
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// DefaultStopwords are particles and titles that are commonly found in the first name part of person data
+// but are not first names themselves.
+var DefaultStopwords = []string{
+	// Nobiliary particles
+	"al", "da", "das", "de", "del", "della", "den", "der", "des", "di", "do", "dos", "du", "el", "la", "le",
+	"ten", "ter", "van", "von", "zu", "zum", "zur",
+
+	// Titles
+	"baron", "dr", "freiherr", "fürst", "graf", "gräfin", "hl", "lady", "lord", "prof", "sankt", "sir", "st",
+}
+
+// Stopwords is a case-insensitive set of tokens that are skipped when extracting first names.
+type Stopwords map[string]struct{}
+
+func NewStopwords(words ...string) Stopwords {
+	s := make(Stopwords)
+	for _, w := range words {
+		s.Add(w)
+	}
+
+	return s
+}
+
+// Add adds a single word to the set.
+func (s Stopwords) Add(word string) {
+	s[strings.ToLower(word)] = struct{}{}
+}
+
+// Contains returns true if the word is part of the set.
+func (s Stopwords) Contains(word string) bool {
+	_, ok := s[strings.ToLower(word)]
+	return ok
+}
+
+// Load adds all words from r to the set. Words are expected one per line, empty lines and lines starting
+// with '#' are ignored.
+func (s Stopwords) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		s.Add(line)
+	}
+
+	return scanner.Err()
+}
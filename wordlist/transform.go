@@ -0,0 +1,40 @@
+package wordlist
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NameTransform replaces a literal string in names.
+type NameTransform struct {
+	From string // String to replace
+	To   string // Replacement
+}
+
+// NameTransforms is a list of transforms that are applied in order.
+type NameTransforms []NameTransform
+
+// ParseNameTransforms parses transforms given as "from=to" pairs.
+func ParseNameTransforms(specs []string) (NameTransforms, error) {
+	var nts NameTransforms
+
+	for _, spec := range specs {
+		i := strings.Index(spec, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid transform, expected from=to: %s", spec)
+		}
+
+		nts = append(nts, NameTransform{From: spec[:i], To: spec[i+1:]})
+	}
+
+	return nts, nil
+}
+
+// Apply returns s with all transforms applied in order.
+func (nts NameTransforms) Apply(s string) string {
+	for _, nt := range nts {
+		s = strings.Replace(s, nt.From, nt.To, -1)
+	}
+
+	return s
+}
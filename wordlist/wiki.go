@@ -0,0 +1,122 @@
+package wordlist
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	PersonDataTemplateRegExpDE = regexp.MustCompile(`(?i:\{\{personendaten([^\}]+)\}\})`)
+	PersonDataTemplateRegExpPL = regexp.MustCompile(`(?i:\{\{persondata([^\}]+)\}\})`)
+	PersonDataTemplateRegExpCS = regexp.MustCompile(`(?i:\{\{osoba([^\}]+)\}\})`)
+	InfoboxPersonRegExpEN      = regexp.MustCompile(`(?i:\{\{\s*infobox[ _](?:person|officeholder|football biography|sportsperson|scientist|writer|military person|artist|philosopher|religious biography|royalty)\s*(\|[^\}]+)\}\})`)
+	PersonCategoryRegExpDE     = regexp.MustCompile(`(?i:\[\[\s*kategorie\s*:\s*(?:geboren|gestorben) )`)
+	PersonCategoryRegExpPL     = regexp.MustCompile(`(?i:\[\[\s*kategoria\s*:\s*(?:urodzeni|zmarli) )`)
+	PersonCategoryRegExpCS     = regexp.MustCompile(`(?i:\[\[\s*kategorie\s*:\s*(?:narození|úmrtí) )`)
+	PersonCategoryRegExpEN     = regexp.MustCompile(`(?i:\[\[\s*category\s*:\s*(?:\d+s? (?:births|deaths)|living people)\b)`)
+	TitleDisambiguationRegExp  = regexp.MustCompile(`\s*\([^\(\)]*\)\s*$`)
+	BirthYearRegExp            = regexp.MustCompile(`\b(1[5-9]\d\d|20\d\d)\b`)
+	TemplateFieldsRegExp       = regexp.MustCompile(`(?i:\s*(\pL+)\s*=[\t\n\f\r '"ʿ]*(.+)[\t\n\f\r '"ʿ]*)`)
+	NameSeperatorRegExp        = regexp.MustCompile(`\s*,\s*`)
+	FirstLastSeparatorRegExp   = regexp.MustCompile(`\s+`)
+	FirstnameSeperatorRegExp   = regexp.MustCompile(`[\t\n\f\r \-\.'"ʿ]`)
+	WikiTemplateRegExp         = regexp.MustCompile(`\{\{[^\{\}]*\}\}`)
+	WikiCategoryRegExp         = regexp.MustCompile(`(?i:\[\[\s*(?:kategorie|kategoria|category)\s*:[^\[\]]*\]\])`)
+	WikiLinkRegExp             = regexp.MustCompile(`\[\[(?:[^\[\]\|]*\|)?([^\[\]\|]*)\]\]`)
+	WikiCommentRegExp          = regexp.MustCompile(`(?s:<!--.*?(?:-->|$))`)
+)
+
+// Wikipedia XML
+type WikipediaRevision struct {
+	ID       int    `xml:"id"`
+	ParentID int    `xml:"parentid"`
+	Text     string `xml:"text"`
+}
+
+type WikipediaRedirect struct {
+	Title string `xml:"title,attr"` // Title of the redirect target
+}
+
+type WikipediaPage struct {
+	Title     string               `xml:"title"`    // Title in text form. (Using spaces, not underscores; with namespace)
+	Namespace string               `xml:"ns"`       // Namespace in canonical form
+	ID        int                  `xml:"id"`       // Optional page ID number
+	Redirect  *WikipediaRedirect   `xml:"redirect"` // Set if the current revision is a redirect
+	Revision  []*WikipediaRevision `xml:"revision"` // Set of revisions
+}
+
+// LatestRevision returns the revision with the highest ID, or the last one if IDs are missing or equal. History
+// dumps list revisions oldest-first, so this is the current text of the page in both dump types.
+func (p *WikipediaPage) LatestRevision() *WikipediaRevision {
+	var latest *WikipediaRevision
+
+	for _, r := range p.Revision {
+		if r != nil && (latest == nil || r.ID >= latest.ID) {
+			latest = r
+		}
+	}
+
+	return latest
+}
+
+// StripWikiMarkup removes comments, templates (including nested ones), and category links from s and replaces
+// wikilinks by their display portion (i.e. "[[John Doe|John]]" becomes "John").
+func StripWikiMarkup(s string) string {
+	s = WikiCommentRegExp.ReplaceAllString(s, "")
+
+	// Remove innermost templates until none are left
+	for {
+		stripped := WikiTemplateRegExp.ReplaceAllString(s, "")
+		if stripped == s {
+			break
+		}
+
+		s = stripped
+	}
+
+	s = WikiCategoryRegExp.ReplaceAllString(s, "")
+
+	return WikiLinkRegExp.ReplaceAllString(s, "$1")
+}
+
+// StripNestedTemplates removes comments and all templates nested within other templates from s, so that
+// the fields of the outer templates can be matched up to their closing braces (i.e. "{{Personendaten|NAME=Doe,
+// John{{Anker|JD}}}}" becomes "{{Personendaten|NAME=Doe, John}}"). Unclosed templates extend to the end of s.
+func StripNestedTemplates(s string) string {
+	if strings.Contains(s, "<!--") {
+		s = WikiCommentRegExp.ReplaceAllString(s, "")
+	}
+
+	var sb strings.Builder
+	depth, last := 0, 0
+
+	for i := 0; i+1 < len(s); i++ {
+		switch {
+		case s[i] == '{' && s[i+1] == '{':
+			depth++
+			if depth == 2 {
+				sb.WriteString(s[last:i])
+			}
+
+			i++
+		case s[i] == '}' && s[i+1] == '}' && depth > 0:
+			depth--
+			if depth == 1 {
+				last = i + 2
+			}
+
+			i++
+		}
+	}
+
+	// Nothing nested
+	if sb.Len() == 0 && last == 0 {
+		return s
+	}
+
+	if depth < 2 {
+		sb.WriteString(s[last:])
+	}
+
+	return sb.String()
+}